@@ -0,0 +1,21 @@
+resource "pa_alarm_template" "weight_limits" {
+  name              = "Weight Limits"
+  alarm_type        = "Variable Limits"
+  custom_text       = "Check the filler scale"
+  cause_required    = true
+  cause_tree_id     = pa_reason_tree.downtime.tree_id
+  default_cause_ids = [pa_event_reason.mechanical.reason_id]
+}
+
+resource "pa_alarm_template_variable" "weight" {
+  template_id = pa_alarm_template.weight_limits.template_id
+  variable_id = pa_variable.weight.variable_id
+}
+
+resource "pa_alarm_rule" "weight_trend" {
+  template_id = pa_alarm_template.weight_limits.template_id
+  rule        = "7 Points Trending"
+  priority    = "Medium"
+  n_value     = 7
+  m_value     = 7
+}
@@ -5,9 +5,79 @@ resource "pa_department" "department1" {
 }
 
 
+resource "pa_security_group" "operators" {
+  description   = "Operators"
+  extended_info = "line operators"
+}
+
+resource "pa_security_role" "shift_leads" {
+  name = "Shift Leads"
+
+  access {
+    security_group_id = pa_security_group.operators.group_id
+    access_level      = "manager"
+  }
+}
+
+variable "jdoe_initial_password" {
+  type      = string
+  sensitive = true
+}
+
+resource "pa_user" "jdoe" {
+  username         = "jdoe"
+  full_name        = "Jane Doe"
+  initial_password = var.jdoe_initial_password
+  language         = "English"
+  role_ids         = [pa_security_role.shift_leads.role_id]
+}
+
+resource "pa_user_group" "operators" {
+  security_group_id = pa_security_group.operators.group_id
+
+  members {
+    user_id      = pa_user.jdoe.user_id
+    access_level = "write"
+  }
+}
+
+
 resource "pa_line" "line1" {
   description   = "Line1"
   extended_info = "some extended info"
   external_link = "https://www.google.com"
   department_id = pa_department.department1.dept_id
-}
\ No newline at end of file
+
+  security_group_id = pa_security_group.operators.group_id
+}
+
+resource "pa_unit" "unit1" {
+  line_id          = pa_line.line1.line_id
+  description      = "Unit1"
+  extended_info    = "some extended info"
+  uses_start_time  = true
+  chain_start_time = false
+
+  security_group_id = pa_security_group.operators.group_id
+}
+
+
+resource "pa_unit_group" "quality" {
+  unit_id     = pa_unit.unit1.unit_id
+  description = "Quality"
+  order       = 1
+}
+
+
+resource "pa_variable" "weight" {
+  unit_group_id     = pa_unit_group.quality.unit_group_id
+  description       = "Weight"
+  data_source       = "Autolog"
+  event_type        = "Production Event"
+  data_type         = "Float"
+  precision         = 2
+  eng_units         = pa_engineering_unit.kg.code
+  sampling_type     = "Last Good Value"
+  sampling_interval = 0
+  security_group_id = pa_security_group.operators.group_id
+}
@@ -0,0 +1,26 @@
+resource "pa_display" "unit1_autolog" {
+  name         = "Unit1 Autolog"
+  group        = "Quality"
+  sheet_type   = "Autolog Time-Based"
+  unit_id      = pa_unit.unit1.unit_id
+  interval     = 60
+  row_headers  = true
+  event_prompt = "Sample"
+
+  variable {
+    title = "Weights"
+  }
+
+  variable {
+    variable_id = pa_variable.weight.variable_id
+  }
+
+  plot {
+    trend_type   = "Individual"
+    variable_ids = [pa_variable.weight.variable_id]
+  }
+
+  options = {
+    "Show Comments" = "1"
+  }
+}
@@ -0,0 +1,30 @@
+# pa_downtime_faults owns the unit's whole fault table. For a single fault on a unit managed
+# elsewhere, use pa_downtime_fault with the same attributes plus unit_id.
+locals {
+  # PLC fault list export: value, name and reason path by reason name.
+  unit1_faults = [
+    { value = "17", name = "Infeed Jam", reasons = ["mechanical", "jam"] },
+    { value = "21", name = "Motor Overload", reasons = ["electrical", "motor"] },
+    { value = "22", name = "Guard Open", reasons = ["mechanical"] },
+  ]
+  reason_ids = {
+    mechanical = pa_event_reason.mechanical.reason_id
+    jam        = pa_event_reason.jam.reason_id
+    electrical = pa_event_reason.electrical.reason_id
+    motor      = pa_event_reason.motor.reason_id
+  }
+}
+
+resource "pa_downtime_faults" "unit1" {
+  unit_id = pa_unit.unit1.unit_id
+
+  dynamic "fault" {
+    for_each = local.unit1_faults
+    content {
+      value       = fault.value.value
+      name        = fault.value.name
+      location_id = pa_unit.unit1.unit_id
+      reason_ids  = [for r in fault.value.reasons : local.reason_ids[r]]
+    }
+  }
+}
@@ -0,0 +1,34 @@
+resource "pa_engineering_unit" "kg" {
+  code        = "kg"
+  description = "Kilograms"
+}
+
+resource "pa_engineering_unit" "lb" {
+  code        = "lb"
+  description = "Pounds"
+}
+
+resource "pa_engineering_unit" "degc" {
+  code        = "degC"
+  description = "Degrees Celsius"
+}
+
+resource "pa_engineering_unit" "degf" {
+  code        = "degF"
+  description = "Degrees Fahrenheit"
+}
+
+resource "pa_engineering_unit_conversion" "kg_to_lb" {
+  description  = "Kilograms to Pounds"
+  from_unit_id = pa_engineering_unit.kg.engineering_unit_id
+  to_unit_id   = pa_engineering_unit.lb.engineering_unit_id
+  slope        = 2.20462
+}
+
+resource "pa_engineering_unit_conversion" "degc_to_degf" {
+  description  = "Celsius to Fahrenheit"
+  from_unit_id = pa_engineering_unit.degc.engineering_unit_id
+  to_unit_id   = pa_engineering_unit.degf.engineering_unit_id
+  slope        = 1.8
+  intercept    = 32
+}
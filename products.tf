@@ -0,0 +1,82 @@
+resource "pa_product_family" "widgets" {
+  description = "Widgets"
+}
+
+resource "pa_product" "widget" {
+  code                   = "WID-001"
+  description            = "Widget 1"
+  family_id              = pa_product_family.widgets.family_id
+  extended_info          = "some extended info"
+  event_esignature_level = 1
+}
+
+resource "pa_product_property" "color" {
+  description = "Color"
+}
+
+resource "pa_product_property" "quality" {
+  description = "Quality"
+}
+
+resource "pa_specification" "weight" {
+  property_id = pa_product_property.quality.property_id
+  description = "Weight"
+  data_type   = "Float"
+  precision   = 2
+  eng_units   = "kg"
+}
+
+resource "pa_unit_products" "unit1" {
+  unit_id       = pa_unit.unit1.unit_id
+  product_codes = [pa_product.widget.code]
+}
+
+resource "pa_product_group" "reporting" {
+  description   = "Reporting Widgets"
+  product_codes = [pa_product.widget.code]
+}
+
+resource "pa_characteristic" "blue" {
+  property_id = pa_product_property.color.property_id
+  description = "Blue"
+}
+
+resource "pa_characteristic" "navy" {
+  property_id = pa_characteristic.blue.property_id
+  description = "Navy"
+  parent_id   = pa_characteristic.blue.characteristic_id
+}
+
+resource "pa_characteristic_group" "cool" {
+  property_id        = pa_characteristic.blue.property_id
+  description        = "Cool Colors"
+  characteristic_ids = [pa_characteristic.blue.characteristic_id, pa_characteristic.navy.characteristic_id]
+}
+
+# Default characteristic for the product; add unit_id to assign it on one unit instead.
+resource "pa_product_characteristic" "widget_color" {
+  product_id        = pa_product.widget.product_id
+  property_id       = pa_characteristic.blue.property_id
+  characteristic_id = pa_characteristic.blue.characteristic_id
+}
+
+resource "pa_var_specs" "weight" {
+  variable_id = pa_variable.weight.variable_id
+
+  limits {
+    product_id = pa_product.widget.product_id
+    l_reject   = "9.5"
+    l_warning  = "9.8"
+    target     = "10"
+    u_warning  = "10.2"
+    u_reject   = "10.5"
+  }
+}
+
+resource "pa_central_spec" "weight_blue" {
+  specification_id  = pa_specification.weight.specification_id
+  characteristic_id = pa_characteristic.blue.characteristic_id
+  l_reject          = "9.5"
+  target            = "10"
+  u_reject          = "10.5"
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// checkUnchanged compares a row read at apply time against the values that were last read
+// into state during refresh. If any attribute moved in the meantime, somebody edited the row
+// after the plan was made and writing the planned values would silently overwrite them.
+func checkUnchanged(d *schema.ResourceData, kind string, current map[string]interface{}) error {
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changed []string
+	for _, key := range keys {
+		old, _ := d.GetChange(key)
+		if fmt.Sprint(old) != fmt.Sprint(current[key]) {
+			changed = append(changed, fmt.Sprintf("  %s: %q -> %q", key, fmt.Sprint(old), fmt.Sprint(current[key])))
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%s %s was changed outside Terraform after it was last refreshed:\n%s\n"+
+		"Refresh and review the plan again before applying", kind, d.Id(), strings.Join(changed, "\n"))
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// refreshedLine is a line as it was last read into state.
+func refreshedLine() *schema.ResourceData {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"description": {Type: schema.TypeString, Optional: true},
+			"group_id":    {Type: schema.TypeInt, Optional: true},
+			"active":      {Type: schema.TypeBool, Optional: true},
+		},
+	}
+	return res.Data(&terraform.InstanceState{
+		ID: "7",
+		Attributes: map[string]string{
+			"id":          "7",
+			"description": "Packaging",
+			"group_id":    "3",
+			"active":      "true",
+		},
+	})
+}
+
+func TestCheckUnchangedAcceptsRefreshedRow(t *testing.T) {
+	// The row comes back with driver types, state holds schema types.
+	err := checkUnchanged(refreshedLine(), "line", map[string]interface{}{
+		"description": "Packaging",
+		"group_id":    int64(3),
+		"active":      true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckUnchangedListsEveryChange(t *testing.T) {
+	err := checkUnchanged(refreshedLine(), "line", map[string]interface{}{
+		"description": "Packaging",
+		"group_id":    int64(4),
+		"active":      false,
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"line 7 was changed outside Terraform",
+		`active: "true" -> "false"`,
+		`group_id: "3" -> "4"`,
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not contain %q", msg, want)
+		}
+	}
+	if strings.Contains(msg, "description") {
+		t.Errorf("error %q lists the unchanged description", msg)
+	}
+	if strings.Index(msg, "active") > strings.Index(msg, "group_id") {
+		t.Errorf("changes are not listed in attribute order: %q", msg)
+	}
+}
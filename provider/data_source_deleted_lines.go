@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceDeletedLines lists lines that spEM_DropLine has marked '<PL Deleted>' but
+// that have not been purged from Prod_Lines_Base yet.
+func dataSourceDeletedLines() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDeletedLinesRead,
+		Schema: map[string]*schema.Schema{
+			"lines": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"line_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"department_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"extended_info": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"external_link": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"security_group_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDeletedLinesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	rows, err := db.QueryContext(ctx, queryLoadDeletedLines)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer rows.Close()
+
+	lines := make([]interface{}, 0)
+	for rows.Next() {
+		var lineID int64
+		var extendedInfo, externalLink sql.NullString
+		var securityGroupID, deptID sql.NullInt64
+		if err := rows.Scan(&lineID, &extendedInfo, &externalLink, &securityGroupID, &deptID); err != nil {
+			return diag.FromErr(err)
+		}
+		lines = append(lines, map[string]interface{}{
+			"line_id":           lineID,
+			"department_id":     nullableInt64ToInt64(deptID),
+			"extended_info":     nullableStringToString(extendedInfo),
+			"external_link":     nullableStringToString(externalLink),
+			"security_group_id": nullableInt64ToInt64(securityGroupID),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := d.Set("lines", lines); err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true, // Falls back to the provider's deletion_protection when not set
+		Description: "Refuse to delete this object until the flag is turned off in a separate apply.",
+	}
+}
+
+// customizeDeletionProtection fills in the provider-wide default when a resource does not
+// set deletion_protection itself, so the effective value is visible in the plan.
+func customizeDeletionProtection(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+	if !config.GetAttr("deletion_protection").IsNull() {
+		return nil
+	}
+
+	def := getMeta(m).DeletionProtection
+	if v, ok := d.GetOk("deletion_protection"); ok && v.(bool) == def {
+		return nil
+	}
+	return d.SetNew("deletion_protection", def)
+}
+
+// checkDeletionProtection is called at the top of Delete. It only looks at the value in
+// state, so turning protection off and destroying has to happen in two applies.
+func checkDeletionProtection(d *schema.ResourceData, kind string) error {
+	if !d.Get("deletion_protection").(bool) {
+		return nil
+	}
+	return fmt.Errorf("%s %s has deletion_protection enabled; set deletion_protection = false and apply before deleting it", kind, d.Id())
+}
+
+// providerOnlyAttributes never reach PA; they only steer what the provider does.
+var providerOnlyAttributes = []string{"deletion_protection", "restore_if_deleted"}
+
+// onlyProviderAttributesChanged reports whether an update only flips provider-side flags.
+// Such an update is kept in state without writing to PA, so it can't trip the
+// out-of-band check or add an audit record.
+func onlyProviderAttributesChanged(d *schema.ResourceData) bool {
+	return !d.HasChangesExcept(providerOnlyAttributes...)
+}
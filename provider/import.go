@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitImportPath splits a "Line/Unit/..." style import ID into exactly the named parts.
+// Plant Applications descriptions can't contain '/', so no escaping is needed. Product codes
+// can, so IDs that include one are split with splitImportPathAround instead.
+func splitImportPath(id string, parts ...string) ([]string, error) {
+	values := strings.Split(id, "/")
+	if len(values) != len(parts) {
+		return nil, fmt.Errorf("unexpected import ID %q, expected %s", id, strings.Join(parts, "/"))
+	}
+	for i, value := range values {
+		if value == "" {
+			return nil, fmt.Errorf("unexpected import ID %q, %s must not be empty", id, parts[i])
+		}
+	}
+	return values, nil
+}
+
+// splitImportPathAround is splitImportPath for IDs where the part at index free may itself
+// contain '/', like a product code. The parts before it are taken from the left, the parts
+// after it from the right, and whatever is left in between is the free part.
+func splitImportPathAround(id string, free int, parts ...string) ([]string, error) {
+	values := strings.Split(id, "/")
+	if len(values) < len(parts) {
+		return nil, fmt.Errorf("unexpected import ID %q, expected %s", id, strings.Join(parts, "/"))
+	}
+	tail := len(values) - (len(parts) - free - 1)
+	result := append([]string{}, values[:free]...)
+	result = append(result, strings.Join(values[free:tail], "/"))
+	result = append(result, values[tail:]...)
+	for i, value := range result {
+		if value == "" {
+			return nil, fmt.Errorf("unexpected import ID %q, %s must not be empty", id, parts[i])
+		}
+	}
+	return result, nil
+}
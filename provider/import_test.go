@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitImportPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		parts   []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "two parts",
+			id:    "Line1/Filler",
+			parts: []string{"line", "unit"},
+			want:  []string{"Line1", "Filler"},
+		},
+		{
+			name:  "three parts with spaces",
+			id:    "Line1/Example Unit/Quality",
+			parts: []string{"line", "unit", "group"},
+			want:  []string{"Line1", "Example Unit", "Quality"},
+		},
+		{
+			name:    "too few parts",
+			id:      "Line1",
+			parts:   []string{"line", "unit"},
+			wantErr: "expected line/unit",
+		},
+		{
+			name:    "too many parts",
+			id:      "Line1/Filler/Weight",
+			parts:   []string{"line", "unit"},
+			wantErr: "expected line/unit",
+		},
+		{
+			name:    "empty part",
+			id:      "Line1//Weight",
+			parts:   []string{"line", "unit", "variable"},
+			wantErr: "unit must not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitImportPath(tt.id, tt.parts...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitImportPathAround(t *testing.T) {
+	parts := []string{"line", "unit", "product code", "property"}
+	tests := []struct {
+		id      string
+		want    []string
+		wantErr string
+	}{
+		{id: "Line1/Filler/WID-001/Color", want: []string{"Line1", "Filler", "WID-001", "Color"}},
+		{id: "Line1/Filler/WID/001/A/Color", want: []string{"Line1", "Filler", "WID/001/A", "Color"}},
+		{id: "Line1/Filler/Color", wantErr: "expected line/unit/product code/property"},
+		{id: "Line1//WID-001/Color", wantErr: "unit must not be empty"},
+		{id: "Line1/Filler/WID-001/", wantErr: "property must not be empty"},
+		{id: "Line1/Filler//Color", wantErr: "product code must not be empty"},
+	}
+
+	for _, tt := range tests {
+		got, err := splitImportPathAround(tt.id, 2, parts...)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q: got error %v, want one containing %q", tt.id, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.id, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.id, got, tt.want)
+		}
+	}
+
+	// With the code first, everything before the last part is the code.
+	got, err := splitImportPathAround("WID/001/Color", 0, "product code", "property")
+	if err != nil || !reflect.DeepEqual(got, []string{"WID/001", "Color"}) {
+		t.Errorf("default characteristic: got %q, %v", got, err)
+	}
+}
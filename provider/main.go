@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"sync"
 	"strconv"
@@ -15,6 +16,16 @@ var (
 	loadOnce          sync.Once
 )
 
+// ProviderMeta is what providerConfigure hands to every resource.
+type ProviderMeta struct {
+	DB             *sql.DB
+	AppLock        bool
+	AppLockName    string
+	AppLockTimeout int
+
+	DeletionProtection bool
+}
+
 func main() {
 	plugin.Serve(&plugin.ServeOpts{
 		ProviderFunc: Provider,
@@ -49,11 +60,72 @@ func Provider() *schema.Provider {
 				Optional:    true,
 				Default:     "SOADB",
 			},
+			"app_lock": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Serialize writes with other instances of this provider through sp_getapplock.",
+			},
+			"app_lock_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "pa_plant_model",
+			},
+			"app_lock_timeout": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				Description:  "Seconds to wait for the application lock before failing.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Default deletion_protection for departments and lines that do not set it.",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"pa_test_unit": resourceTestUnit(),
 			"pa_department": resourceDepartment(),
 			"pa_line": resourceLine(),
+			"pa_unit": resourceUnit(),
+			"pa_unit_group": resourceUnitGroup(),
+			"pa_variable": resourceVariable(),
+			"pa_product_family": resourceProductFamily(),
+			"pa_product": resourceProduct(),
+			"pa_unit_products": resourceUnitProducts(),
+			"pa_product_group": resourceProductGroup(),
+			"pa_characteristic": resourceCharacteristic(),
+			"pa_characteristic_group": resourceCharacteristicGroup(),
+			"pa_product_characteristic": resourceProductCharacteristic(),
+			"pa_product_property": resourceProductProperty(),
+			"pa_specification": resourceSpecification(),
+			"pa_var_specs": resourceVarSpecs(),
+			"pa_central_spec": resourceCentralSpec(),
+			"pa_security_group": resourceSecurityGroup(),
+			"pa_security_role": resourceSecurityRole(),
+			"pa_user": resourceUser(),
+			"pa_user_group": resourceUserGroup(),
+			"pa_event_reason": resourceEventReason(),
+			"pa_reason_tree": resourceReasonTree(),
+			"pa_reason_category": resourceReasonCategory(),
+			"pa_reason_tree_category": resourceReasonTreeCategory(),
+			"pa_reason_shortcut": resourceReasonShortcut(),
+			"pa_waste_event_type": resourceWasteEventType(),
+			"pa_waste_measure": resourceWasteMeasure(),
+			"pa_waste_fault": resourceWasteFault(),
+			"pa_downtime_fault": resourceDowntimeFault(),
+			"pa_downtime_faults": resourceDowntimeFaults(),
+			"pa_alarm_template": resourceAlarmTemplate(),
+			"pa_alarm_template_variable": resourceAlarmTemplateVariable(),
+			"pa_alarm_rule": resourceAlarmRule(),
+			"pa_engineering_unit": resourceEngineeringUnit(),
+			"pa_engineering_unit_conversion": resourceEngineeringUnitConversion(),
+			"pa_display": resourceDisplay(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"pa_deleted_lines": dataSourceDeletedLines(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -75,11 +147,22 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		return nil, diag.FromErr(err)
 	}
 
-	return db, nil
+	return &ProviderMeta{
+		DB:             db,
+		AppLock:        d.Get("app_lock").(bool),
+		AppLockName:    d.Get("app_lock_name").(string),
+		AppLockTimeout: d.Get("app_lock_timeout").(int),
+
+		DeletionProtection: d.Get("deletion_protection").(bool),
+	}, nil
+}
+
+func getMeta(m interface{}) *ProviderMeta {
+	return m.(*ProviderMeta)
 }
 
 func getDB(m interface{}) *sql.DB {
-	return m.(*sql.DB)
+	return getMeta(m).DB
 }
 
 func stringToNullString(value string) sql.NullString {
@@ -110,6 +193,29 @@ func int64ToNullInt64(value int64) sql.NullInt64 {
 	return sql.NullInt64{Int64: value, Valid: true}
 }
 
+// nullableIdToInt64 maps a NULL foreign key to 0, which is what an unset optional
+// TypeInt attribute reads as, so unset references don't show a perpetual diff.
+func nullableIdToInt64(value sql.NullInt64) int64 {
+	if !value.Valid {
+		return 0
+	}
+	return value.Int64
+}
+
+func idToNullInt64(value int64) sql.NullInt64 {
+	if value <= 0 {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: value, Valid: true}
+}
+
+func boolToInt64(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
 func int64ToString(value int64) string {
 	return strconv.FormatInt(value, 10)
 }
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type AlarmRule struct {
+	Priority          string
+	Spc_Variable_Type string
+	Fire_Priority     int64
+	N_Value           int64
+	M_Value           int64
+}
+
+const (
+	queryGetAlarmRule = `
+		SELECT	AP.AP_Desc, SGVT.SPC_Group_Variable_Type_Desc, ATSRD.Firing_Priority, ATSRD.N_Value, ATSRD.M_Value
+		FROM dbo.Alarm_Template_SPC_Rule_Data AS ATSRD
+		LEFT JOIN dbo.Alarm_Priorities AS AP ON AP.AP_Id = ATSRD.AP_Id
+		LEFT JOIN dbo.SPC_Group_Variable_Types AS SGVT ON SGVT.SPC_Group_Variable_Type_Id = ATSRD.SPC_Group_Variable_Type_Id
+		WHERE ATSRD.AT_Id = @param_at_id
+		AND ATSRD.Alarm_SPC_Rule_Id = @param_rule_id;
+		`
+
+	queryGetAlarmRuleForUpdate = `
+		SELECT	AP.AP_Desc, SGVT.SPC_Group_Variable_Type_Desc, ATSRD.Firing_Priority, ATSRD.N_Value, ATSRD.M_Value
+		FROM dbo.Alarm_Template_SPC_Rule_Data AS ATSRD WITH (UPDLOCK, HOLDLOCK)
+		LEFT JOIN dbo.Alarm_Priorities AS AP ON AP.AP_Id = ATSRD.AP_Id
+		LEFT JOIN dbo.SPC_Group_Variable_Types AS SGVT ON SGVT.SPC_Group_Variable_Type_Id = ATSRD.SPC_Group_Variable_Type_Id
+		WHERE ATSRD.AT_Id = @param_at_id
+		AND ATSRD.Alarm_SPC_Rule_Id = @param_rule_id;
+		`
+
+	queryGetAlarmSPCRuleIdByDesc = `
+		SELECT ASR.Alarm_SPC_Rule_Id
+		FROM dbo.Alarm_SPC_Rules AS ASR
+		WHERE ASR.Alarm_SPC_Rule_Desc = @param_rule_desc;
+		`
+
+	queryGetAlarmSPCRuleDesc = `
+		SELECT ASR.Alarm_SPC_Rule_Desc
+		FROM dbo.Alarm_SPC_Rules AS ASR
+		WHERE ASR.Alarm_SPC_Rule_Id = @param_rule_id;
+		`
+
+	// spEM_IEImportAlarmRules adds or updates one SPC rule of the template named by @Desc.
+	queryImportAlarmRule = `
+		SET XACT_ABORT ON;
+
+		DECLARE @at_desc			NVARCHAR(100),
+				@rule_desc			NVARCHAR(100);
+
+		SET @at_desc = (
+			SELECT AT.AT_Desc FROM dbo.Alarm_Templates AS AT WHERE AT.AT_Id = @param_at_id);
+
+		IF @at_desc IS NULL
+			THROW 50000, 'alarm template not found', 1;
+
+		SET @rule_desc = (
+			SELECT ASR.Alarm_SPC_Rule_Desc FROM dbo.Alarm_SPC_Rules AS ASR WHERE ASR.Alarm_SPC_Rule_Id = @param_rule_id);
+
+		IF @rule_desc IS NULL
+			THROW 50000, 'SPC rule not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportAlarmRules]
+				@Desc = @at_desc,
+				@Rule = @rule_desc,
+				@Priority = @param_priority,
+				@SPCVarType = @param_spc_var_type,
+				@FirePriority = @param_fire_priority,
+				@nValue = @param_n_value,
+				@mValue = @param_m_value,
+				@UserId = @param_user_id;
+		`
+
+	queryDeleteAlarmRule = `
+		DELETE FROM dbo.Alarm_Template_SPC_Rule_Data
+		WHERE AT_Id = @param_at_id
+		AND Alarm_SPC_Rule_Id = @param_rule_id;
+		`
+)
+
+// resourceAlarmRule enables one SPC rule on an alarm template.
+func resourceAlarmRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAlarmRuleCreate,
+		ReadContext:   resourceAlarmRuleRead,
+		UpdateContext: resourceAlarmRuleUpdate,
+		DeleteContext: resourceAlarmRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlarmRuleImport,
+		},
+		CustomizeDiff: resourceAlarmRuleCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"template_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"rule": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Alarm_SPC_Rule_Desc from dbo.Alarm_SPC_Rules.",
+			},
+			"priority": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "AP_Desc from dbo.Alarm_Priorities.",
+			},
+			"spc_variable_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "SPC_Group_Variable_Type_Desc from dbo.SPC_Group_Variable_Types, for SPC group templates.",
+			},
+			"fire_priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Order the rule is evaluated in when several rules match.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"n_value": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Number of points the rule looks at.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"m_value": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Number of those points that must break the rule.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+		},
+	}
+}
+
+func resourceAlarmRuleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	n := d.Get("n_value").(int)
+	mValue := d.Get("m_value").(int)
+	if n != 0 && mValue > n {
+		return fmt.Errorf("m_value (%d) must not be greater than n_value (%d)", mValue, n)
+	}
+	return nil
+}
+
+// parseAlarmRuleId splits the "template/rule" ID.
+func parseAlarmRuleId(id string) (templateID, ruleID int64, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected alarm rule ID %q", id)
+	}
+	if templateID, err = stringToInt64(parts[0]); err != nil {
+		return
+	}
+	ruleID, err = stringToInt64(parts[1])
+	return
+}
+
+func scanAlarmRule(row interface{ Scan(...interface{}) error }) (*AlarmRule, error) {
+	var rule AlarmRule
+	var priority, spcVarType sql.NullString
+	var firePriority, nValue, mValue sql.NullInt64
+	if err := row.Scan(&priority, &spcVarType, &firePriority, &nValue, &mValue); err != nil {
+		return nil, err
+	}
+	rule.Priority = nullableStringToString(priority)
+	rule.Spc_Variable_Type = nullableStringToString(spcVarType)
+	rule.Fire_Priority = firePriority.Int64
+	rule.N_Value = nValue.Int64
+	rule.M_Value = mValue.Int64
+	return &rule, nil
+}
+
+func execImportAlarmRule(ctx context.Context, tx *sql.Tx, templateID, ruleID int64, d *schema.ResourceData) error {
+	userId := 1
+
+	optionalInt := func(key string) sql.NullString {
+		if v := d.Get(key).(int); v != 0 {
+			return sql.NullString{String: int64ToString(int64(v)), Valid: true}
+		}
+		return sql.NullString{}
+	}
+
+	var returnValue sql.NullInt64
+	_, err := tx.ExecContext(ctx, queryImportAlarmRule,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_at_id", templateID),
+		sql.Named("param_rule_id", ruleID),
+		sql.Named("param_priority", d.Get("priority").(string)),
+		sql.Named("param_spc_var_type", stringToNullString(d.Get("spc_variable_type").(string))),
+		sql.Named("param_fire_priority", optionalInt("fire_priority")),
+		sql.Named("param_n_value", optionalInt("n_value")),
+		sql.Named("param_m_value", optionalInt("m_value")),
+		sql.Named("param_user_id", userId),
+	)
+	if err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceAlarmRuleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	templateID := int64(d.Get("template_id").(int))
+	ruleDesc := d.Get("rule").(string)
+
+	var ruleID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetAlarmSPCRuleIdByDesc, sql.Named("param_rule_desc", ruleDesc)).Scan(&ruleID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("SPC rule %q not found", ruleDesc)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = scanAlarmRule(tx.QueryRowContext(ctx, queryGetAlarmRule,
+			sql.Named("param_at_id", templateID),
+			sql.Named("param_rule_id", ruleID),
+		))
+		if err == nil {
+			return fmt.Errorf("rule %q is already on alarm template %d; import it instead", ruleDesc, templateID)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportAlarmRule(ctx, tx, templateID, ruleID, d); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d", templateID, ruleID))
+	return resourceAlarmRuleRead(ctx, d, m)
+}
+
+func resourceAlarmRuleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	templateID, ruleID, err := parseAlarmRuleId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	rule, err := scanAlarmRule(db.QueryRowContext(ctx, queryGetAlarmRule,
+		sql.Named("param_at_id", templateID),
+		sql.Named("param_rule_id", ruleID),
+	))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var ruleDesc sql.NullString
+	err = db.QueryRowContext(ctx, queryGetAlarmSPCRuleDesc, sql.Named("param_rule_id", ruleID)).Scan(&ruleDesc)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("template_id", templateID)
+	d.Set("rule", nullableStringToString(ruleDesc))
+	d.Set("priority", rule.Priority)
+	d.Set("spc_variable_type", rule.Spc_Variable_Type)
+	d.Set("fire_priority", rule.Fire_Priority)
+	d.Set("n_value", rule.N_Value)
+	d.Set("m_value", rule.M_Value)
+	return nil
+}
+
+func resourceAlarmRuleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	templateID, ruleID, err := parseAlarmRuleId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanAlarmRule(tx.QueryRowContext(ctx, queryGetAlarmRuleForUpdate,
+			sql.Named("param_at_id", templateID),
+			sql.Named("param_rule_id", ruleID),
+		))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("rule %d is no longer on alarm template %d", ruleID, templateID)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "alarm rule", map[string]interface{}{
+			"priority":          current.Priority,
+			"spc_variable_type": current.Spc_Variable_Type,
+			"fire_priority":     current.Fire_Priority,
+			"n_value":           current.N_Value,
+			"m_value":           current.M_Value,
+		}); err != nil {
+			return err
+		}
+
+		return execImportAlarmRule(ctx, tx, templateID, ruleID, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAlarmRuleRead(ctx, d, m)
+}
+
+func resourceAlarmRuleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	templateID, ruleID, err := parseAlarmRuleId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteAlarmRule,
+			sql.Named("param_at_id", templateID),
+			sql.Named("param_rule_id", ruleID),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceAlarmRuleImport accepts "Template/Rule".
+func resourceAlarmRuleImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	db := getDB(m)
+
+	parts, err := splitImportPath(d.Id(), "Template", "Rule")
+	if err != nil {
+		return nil, err
+	}
+
+	var templateID, ruleID int64
+	err = db.QueryRowContext(ctx, queryGetAlarmTemplateIdByName, sql.Named("param_at_desc", parts[0])).Scan(&templateID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("alarm template %q not found", parts[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = db.QueryRowContext(ctx, queryGetAlarmSPCRuleIdByDesc, sql.Named("param_rule_desc", parts[1])).Scan(&ruleID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("SPC rule %q not found", parts[1])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d", templateID, ruleID))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,567 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type AlarmTemplate struct {
+	Template_Id              int64
+	Name                     string
+	Custom_Text              string
+	Use_Variable_Desc        bool
+	Use_Template_Desc        bool
+	Use_Trigger_Desc         bool
+	Data_Quality_Variable_Id int64
+	Data_Quality_Criteria    string
+	Data_Quality_Value       string
+	Cause_Required           bool
+	Cause_Tree_Id            int64
+	Default_Cause_Ids        []interface{}
+	Action_Required          bool
+	Action_Tree_Id           int64
+	Default_Action_Ids       []interface{}
+	Alarm_Type               string
+	Esignature_Level         int64
+	Sp_Name                  string
+}
+
+const (
+	queryGetAlarmTemplate = `
+		SELECT	AT.AT_Id, AT.AT_Desc, AT.Custom_Text, AT.Use_Var_Desc, AT.Use_AT_Desc, AT.Use_Trigger_Desc,
+				AT.DQ_Var_Id, CAST(AT.DQ_Criteria AS NVARCHAR(50)), AT.DQ_Value,
+				AT.Cause_Required, AT.Cause_Tree_Id,
+				AT.Default_Cause1, AT.Default_Cause2, AT.Default_Cause3, AT.Default_Cause4,
+				AT.Action_Required, AT.Action_Tree_Id,
+				AT.Default_Action1, AT.Default_Action2, AT.Default_Action3, AT.Default_Action4,
+				ATY.Alarm_Type_Desc, AT.ESignature_Level, AT.SP_Name
+		FROM dbo.Alarm_Templates AS AT
+		LEFT JOIN dbo.Alarm_Types AS ATY ON ATY.Alarm_Type_Id = AT.Alarm_Type_Id
+		WHERE AT.AT_Id = @param_at_id;
+		`
+
+	queryGetAlarmTemplateForUpdate = `
+		SELECT	AT.AT_Id, AT.AT_Desc, AT.Custom_Text, AT.Use_Var_Desc, AT.Use_AT_Desc, AT.Use_Trigger_Desc,
+				AT.DQ_Var_Id, CAST(AT.DQ_Criteria AS NVARCHAR(50)), AT.DQ_Value,
+				AT.Cause_Required, AT.Cause_Tree_Id,
+				AT.Default_Cause1, AT.Default_Cause2, AT.Default_Cause3, AT.Default_Cause4,
+				AT.Action_Required, AT.Action_Tree_Id,
+				AT.Default_Action1, AT.Default_Action2, AT.Default_Action3, AT.Default_Action4,
+				ATY.Alarm_Type_Desc, AT.ESignature_Level, AT.SP_Name
+		FROM dbo.Alarm_Templates AS AT WITH (UPDLOCK, HOLDLOCK)
+		LEFT JOIN dbo.Alarm_Types AS ATY ON ATY.Alarm_Type_Id = AT.Alarm_Type_Id
+		WHERE AT.AT_Id = @param_at_id;
+		`
+
+	queryGetAlarmTemplateIdByName = `
+		SELECT AT.AT_Id
+		FROM dbo.Alarm_Templates AS AT
+		WHERE AT.AT_Desc = @param_at_desc;
+		`
+
+	// spEM_IEImportAlarmTemplates creates or updates the template named AT_Desc. Trees, reasons and
+	// the data quality variable are passed by description.
+	queryImportAlarmTemplate = `
+		SET XACT_ABORT ON;
+
+		DECLARE @dq_pl_desc			NVARCHAR(50),
+				@dq_pu_desc			NVARCHAR(50),
+				@dq_var_desc		NVARCHAR(50),
+				@cause_tree_name	NVARCHAR(50),
+				@action_tree_name	NVARCHAR(50),
+				@c1					NVARCHAR(100),
+				@c2					NVARCHAR(100),
+				@c3					NVARCHAR(100),
+				@c4					NVARCHAR(100),
+				@a1					NVARCHAR(100),
+				@a2					NVARCHAR(100),
+				@a3					NVARCHAR(100),
+				@a4					NVARCHAR(100);
+
+		IF @param_dq_var_id IS NOT NULL
+		BEGIN
+			SELECT	@dq_pl_desc = PLB.PL_Desc, @dq_pu_desc = PUB.PU_Desc, @dq_var_desc = VB.Var_Desc
+			FROM dbo.Variables_Base AS VB
+			JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+			JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+			WHERE VB.Var_Id = @param_dq_var_id;
+
+			IF @dq_var_desc IS NULL
+				THROW 50000, 'data quality variable not found', 1;
+		END
+
+		IF @param_cause_tree_id IS NOT NULL
+		BEGIN
+			SET @cause_tree_name = (
+				SELECT ERT.Tree_Name FROM dbo.Event_Reason_Tree AS ERT WHERE ERT.Tree_Name_Id = @param_cause_tree_id);
+
+			IF @cause_tree_name IS NULL
+				THROW 50000, 'cause tree not found', 1;
+		END
+
+		IF @param_action_tree_id IS NOT NULL
+		BEGIN
+			SET @action_tree_name = (
+				SELECT ERT.Tree_Name FROM dbo.Event_Reason_Tree AS ERT WHERE ERT.Tree_Name_Id = @param_action_tree_id);
+
+			IF @action_tree_name IS NULL
+				THROW 50000, 'action tree not found', 1;
+		END
+
+		SET @c1 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_cause1);
+		SET @c2 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_cause2);
+		SET @c3 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_cause3);
+		SET @c4 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_cause4);
+		SET @a1 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_action1);
+		SET @a2 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_action2);
+		SET @a3 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_action3);
+		SET @a4 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_action4);
+
+		EXEC	@return_value = [dbo].[spEM_IEImportAlarmTemplates]
+				@AT_Desc = @param_at_desc,
+				@Custom_Text = @param_custom_text,
+				@sUse_Var_Desc = @param_use_var_desc,
+				@sUse_AT_Desc = @param_use_at_desc,
+				@sUse_Trigger_Desc = @param_use_trigger_desc,
+				@DQ_PL_Desc = @dq_pl_desc,
+				@DQ_PU_Desc = @dq_pu_desc,
+				@DQ_Var_Desc = @dq_var_desc,
+				@DQ_Criteria = @param_dq_criteria,
+				@DQ_Value = @param_dq_value,
+				@sCause_Required = @param_cause_required,
+				@Cause_Tree_Name = @cause_tree_name,
+				@Default_Cause_Name1 = @c1,
+				@Default_Cause_Name2 = @c2,
+				@Default_Cause_Name3 = @c3,
+				@Default_Cause_Name4 = @c4,
+				@sAction_Required = @param_action_required,
+				@Action_Tree_Name = @action_tree_name,
+				@Default_Action_Name1 = @a1,
+				@Default_Action_Name2 = @a2,
+				@Default_Action_Name3 = @a3,
+				@Default_Action_Name4 = @a4,
+				@Comment_Text = NULL,
+				@AlarmType = @param_alarm_type,
+				@EsigLevel = @param_esig_level,
+				@SpName = @param_sp_name,
+				@User_Id = @param_user_id;
+		`
+
+	queryRenameAlarmTemplate = `
+		UPDATE dbo.Alarm_Templates SET
+			AT_Desc = @param_at_desc
+		WHERE AT_Id = @param_at_id;
+		`
+
+	queryDeleteAlarmTemplate = `
+		SET XACT_ABORT ON;
+
+		IF EXISTS (SELECT 1 FROM dbo.Alarm_Template_Var_Data AS ATVD WHERE ATVD.AT_Id = @param_at_id)
+			THROW 50000, 'alarm template still has variables', 1;
+
+		IF EXISTS (SELECT 1 FROM dbo.Alarm_Template_SPC_Rule_Data AS ATSRD WHERE ATSRD.AT_Id = @param_at_id)
+			THROW 50000, 'alarm template still has rules', 1;
+
+		DELETE FROM dbo.Alarm_Templates WHERE AT_Id = @param_at_id;
+		`
+)
+
+func resourceAlarmTemplate() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAlarmTemplateCreate,
+		ReadContext:   resourceAlarmTemplateRead,
+		UpdateContext: resourceAlarmTemplateUpdate,
+		DeleteContext: resourceAlarmTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlarmTemplateImport,
+		},
+		CustomizeDiff: resourceAlarmTemplateCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"template_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "AT_Desc.",
+				ValidateFunc: validation.StringLenBetween(1, 50),
+			},
+			"alarm_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Alarm_Type_Desc from dbo.Alarm_Types.",
+			},
+			"custom_text": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"use_variable_desc": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"use_template_desc": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"use_trigger_desc": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"data_quality_variable_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"data_quality_criteria": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar50(),
+			},
+			"data_quality_value": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar50(),
+			},
+			"cause_required": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"cause_tree_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"default_cause_ids": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    reasonTreeDepth,
+				Description: "Reason path in the cause tree, from level 1 down.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"action_required": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"action_tree_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"default_action_ids": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    reasonTreeDepth,
+				Description: "Reason path in the action tree, from level 1 down.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"esignature_level": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "0 for none, 1 for user, 2 for approver.",
+				ValidateFunc: validation.IntBetween(0, 2),
+			},
+			"sp_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Stored procedure run when the alarm fires.",
+				ValidateFunc: validateVarchar50(),
+			},
+		},
+	}
+}
+
+// resourceAlarmTemplateCustomizeDiff rejects causes or actions that have no tree to come from.
+// Whether the default reasons are a path in the tree is checked on apply.
+func resourceAlarmTemplateCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, kind := range []string{"cause", "action"} {
+		treeKey := kind + "_tree_id"
+		if !d.NewValueKnown(treeKey) || d.Get(treeKey).(int) != 0 {
+			continue
+		}
+		if d.Get(kind + "_required").(bool) {
+			return fmt.Errorf("%s_required needs %s", kind, treeKey)
+		}
+		if len(d.Get("default_"+kind+"_ids").([]interface{})) > 0 {
+			return fmt.Errorf("default_%s_ids needs %s", kind, treeKey)
+		}
+	}
+	return nil
+}
+
+func scanAlarmTemplate(row interface{ Scan(...interface{}) error }) (*AlarmTemplate, error) {
+	var t AlarmTemplate
+	var name, customText, dqCriteria, dqValue, alarmType, spName sql.NullString
+	var useVarDesc, useATDesc, useTriggerDesc, causeRequired, actionRequired sql.NullBool
+	var dqVarID, causeTreeID, actionTreeID, esigLevel sql.NullInt64
+	causes := make([]sql.NullInt64, reasonTreeDepth)
+	actions := make([]sql.NullInt64, reasonTreeDepth)
+	if err := row.Scan(&t.Template_Id, &name, &customText, &useVarDesc, &useATDesc, &useTriggerDesc,
+		&dqVarID, &dqCriteria, &dqValue,
+		&causeRequired, &causeTreeID, &causes[0], &causes[1], &causes[2], &causes[3],
+		&actionRequired, &actionTreeID, &actions[0], &actions[1], &actions[2], &actions[3],
+		&alarmType, &esigLevel, &spName); err != nil {
+		return nil, err
+	}
+	t.Name = nullableStringToString(name)
+	t.Custom_Text = nullableStringToString(customText)
+	t.Use_Variable_Desc = useVarDesc.Bool
+	t.Use_Template_Desc = useATDesc.Bool
+	t.Use_Trigger_Desc = useTriggerDesc.Bool
+	t.Data_Quality_Variable_Id = nullableIdToInt64(dqVarID)
+	t.Data_Quality_Criteria = nullableStringToString(dqCriteria)
+	t.Data_Quality_Value = nullableStringToString(dqValue)
+	t.Cause_Required = causeRequired.Bool
+	t.Cause_Tree_Id = nullableIdToInt64(causeTreeID)
+	t.Default_Cause_Ids = scanFaultReasons(causes)
+	t.Action_Required = actionRequired.Bool
+	t.Action_Tree_Id = nullableIdToInt64(actionTreeID)
+	t.Default_Action_Ids = scanFaultReasons(actions)
+	t.Alarm_Type = nullableStringToString(alarmType)
+	t.Esignature_Level = nullableIdToInt64(esigLevel)
+	t.Sp_Name = nullableStringToString(spName)
+	return &t, nil
+}
+
+// reasonPathArgs renders a reason path as @<prefix>1..4; missing levels are NULL.
+func reasonPathArgs(prefix string, reasonIDs []interface{}) []interface{} {
+	var args []interface{}
+	for i := 0; i < reasonTreeDepth; i++ {
+		value := sql.NullInt64{}
+		if i < len(reasonIDs) {
+			value = sql.NullInt64{Int64: int64(reasonIDs[i].(int)), Valid: true}
+		}
+		args = append(args, sql.Named(fmt.Sprintf("%s%d", prefix, i+1), value))
+	}
+	return args
+}
+
+// checkReasonTreePath fails unless reasonIDs is a path of nodes in the tree. An empty path is
+// always valid.
+func checkReasonTreePath(ctx context.Context, tx *sql.Tx, what string, treeID int64, reasonIDs []interface{}) error {
+	if len(reasonIDs) == 0 {
+		return nil
+	}
+	nodes, err := loadReasonTreeNodes(tx.QueryContext(ctx, queryLoadReasonTreeNodes, sql.Named("param_tree_id", treeID)))
+	if err != nil {
+		return err
+	}
+	path := reasonPathFromList(reasonIDs)
+	if findReasonTreeNode(nodes, path) == nil {
+		return fmt.Errorf("%s %s is not a path in reason tree %d", what, reasonTreePathKey(path), treeID)
+	}
+	return nil
+}
+
+func execImportAlarmTemplate(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) error {
+	userId := 1
+
+	causeTreeID := int64(d.Get("cause_tree_id").(int))
+	causes := d.Get("default_cause_ids").([]interface{})
+	if err := checkReasonTreePath(ctx, tx, "default causes", causeTreeID, causes); err != nil {
+		return err
+	}
+	actionTreeID := int64(d.Get("action_tree_id").(int))
+	actions := d.Get("default_action_ids").([]interface{})
+	if err := checkReasonTreePath(ctx, tx, "default actions", actionTreeID, actions); err != nil {
+		return err
+	}
+
+	var returnValue sql.NullInt64
+	args := append(reasonPathArgs("param_cause", causes), reasonPathArgs("param_action", actions)...)
+	args = append(args,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_at_desc", d.Get("name").(string)),
+		sql.Named("param_custom_text", stringToNullString(d.Get("custom_text").(string))),
+		sql.Named("param_use_var_desc", int64ToString(boolToInt64(d.Get("use_variable_desc").(bool)))),
+		sql.Named("param_use_at_desc", int64ToString(boolToInt64(d.Get("use_template_desc").(bool)))),
+		sql.Named("param_use_trigger_desc", int64ToString(boolToInt64(d.Get("use_trigger_desc").(bool)))),
+		sql.Named("param_dq_var_id", idToNullInt64(int64(d.Get("data_quality_variable_id").(int)))),
+		sql.Named("param_dq_criteria", stringToNullString(d.Get("data_quality_criteria").(string))),
+		sql.Named("param_dq_value", stringToNullString(d.Get("data_quality_value").(string))),
+		sql.Named("param_cause_required", int64ToString(boolToInt64(d.Get("cause_required").(bool)))),
+		sql.Named("param_cause_tree_id", idToNullInt64(causeTreeID)),
+		sql.Named("param_action_required", int64ToString(boolToInt64(d.Get("action_required").(bool)))),
+		sql.Named("param_action_tree_id", idToNullInt64(actionTreeID)),
+		sql.Named("param_alarm_type", d.Get("alarm_type").(string)),
+		sql.Named("param_esig_level", int64ToString(int64(d.Get("esignature_level").(int)))),
+		sql.Named("param_sp_name", stringToNullString(d.Get("sp_name").(string))),
+		sql.Named("param_user_id", userId),
+	)
+	if _, err := tx.ExecContext(ctx, queryImportAlarmTemplate, args...); err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceAlarmTemplateCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	var templateID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetAlarmTemplateIdByName, sql.Named("param_at_desc", name)).Scan(&templateID)
+		if err == nil {
+			return fmt.Errorf("alarm template %q already exists; import it instead", name)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportAlarmTemplate(ctx, tx, d); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetAlarmTemplateIdByName, sql.Named("param_at_desc", name)).Scan(&templateID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("alarm template %q was not created", name)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("template_id", int(templateID))
+	d.SetId(int64ToString(templateID))
+	return resourceAlarmTemplateRead(ctx, d, m)
+}
+
+func resourceAlarmTemplateRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	t, err := scanAlarmTemplate(db.QueryRowContext(ctx, queryGetAlarmTemplate, sql.Named("param_at_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("template_id", t.Template_Id)
+	d.Set("name", t.Name)
+	d.Set("alarm_type", t.Alarm_Type)
+	d.Set("custom_text", t.Custom_Text)
+	d.Set("use_variable_desc", t.Use_Variable_Desc)
+	d.Set("use_template_desc", t.Use_Template_Desc)
+	d.Set("use_trigger_desc", t.Use_Trigger_Desc)
+	d.Set("data_quality_variable_id", t.Data_Quality_Variable_Id)
+	d.Set("data_quality_criteria", t.Data_Quality_Criteria)
+	d.Set("data_quality_value", t.Data_Quality_Value)
+	d.Set("cause_required", t.Cause_Required)
+	d.Set("cause_tree_id", t.Cause_Tree_Id)
+	d.Set("default_cause_ids", t.Default_Cause_Ids)
+	d.Set("action_required", t.Action_Required)
+	d.Set("action_tree_id", t.Action_Tree_Id)
+	d.Set("default_action_ids", t.Default_Action_Ids)
+	d.Set("esignature_level", t.Esignature_Level)
+	d.Set("sp_name", t.Sp_Name)
+	return nil
+}
+
+func resourceAlarmTemplateUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanAlarmTemplate(tx.QueryRowContext(ctx, queryGetAlarmTemplateForUpdate, sql.Named("param_at_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("alarm template %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "alarm template", map[string]interface{}{
+			"name":                     current.Name,
+			"custom_text":              current.Custom_Text,
+			"use_variable_desc":        current.Use_Variable_Desc,
+			"use_template_desc":        current.Use_Template_Desc,
+			"use_trigger_desc":         current.Use_Trigger_Desc,
+			"data_quality_variable_id": current.Data_Quality_Variable_Id,
+			"data_quality_criteria":    current.Data_Quality_Criteria,
+			"data_quality_value":       current.Data_Quality_Value,
+			"cause_required":           current.Cause_Required,
+			"cause_tree_id":            current.Cause_Tree_Id,
+			"default_cause_ids":        current.Default_Cause_Ids,
+			"action_required":          current.Action_Required,
+			"action_tree_id":           current.Action_Tree_Id,
+			"default_action_ids":       current.Default_Action_Ids,
+			"esignature_level":         current.Esignature_Level,
+			"sp_name":                  current.Sp_Name,
+		}); err != nil {
+			return err
+		}
+
+		// The procedure finds the template by AT_Desc, so a new name is written first.
+		if d.HasChange("name") {
+			_, err := tx.ExecContext(ctx, queryRenameAlarmTemplate,
+				sql.Named("param_at_id", id),
+				sql.Named("param_at_desc", d.Get("name").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return execImportAlarmTemplate(ctx, tx, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAlarmTemplateRead(ctx, d, m)
+}
+
+func resourceAlarmTemplateDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteAlarmTemplate, sql.Named("param_at_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceAlarmTemplateImport accepts the template name.
+func resourceAlarmTemplateImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetAlarmTemplateIdByName,
+		sql.Named("param_at_desc", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("alarm template %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
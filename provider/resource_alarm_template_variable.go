@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetAlarmTemplateVariable = `
+		SELECT EG.EG_Desc
+		FROM dbo.Alarm_Template_Var_Data AS ATVD
+		LEFT JOIN dbo.Email_Groups AS EG ON EG.EG_Id = ATVD.EG_Id
+		WHERE ATVD.AT_Id = @param_at_id
+		AND ATVD.Var_Id = @param_var_id;
+		`
+
+	queryGetAlarmTemplateVariableForUpdate = `
+		SELECT EG.EG_Desc
+		FROM dbo.Alarm_Template_Var_Data AS ATVD WITH (UPDLOCK, HOLDLOCK)
+		LEFT JOIN dbo.Email_Groups AS EG ON EG.EG_Id = ATVD.EG_Id
+		WHERE ATVD.AT_Id = @param_at_id
+		AND ATVD.Var_Id = @param_var_id;
+		`
+
+	queryGetAlarmTemplateVariableIdsByPath = `
+		SELECT AT.AT_Id, VB.Var_Id
+		FROM dbo.Alarm_Template_Var_Data AS ATVD
+		JOIN dbo.Alarm_Templates AS AT ON AT.AT_Id = ATVD.AT_Id
+		JOIN dbo.Variables_Base AS VB ON VB.Var_Id = ATVD.Var_Id
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE AT.AT_Desc = @param_at_desc
+		AND PLB.PL_Desc = @param_pl_desc
+		AND PUB.PU_Desc = @param_pu_desc
+		AND VB.Var_Desc = @param_var_desc;
+		`
+
+	// spEM_IEImportAlarmTemplateData assigns the variable to the template.
+	queryAddAlarmTemplateVariable = `
+		SET XACT_ABORT ON;
+
+		DECLARE @at_desc			NVARCHAR(50),
+				@pl_desc			NVARCHAR(50),
+				@pu_desc			NVARCHAR(50),
+				@var_desc			NVARCHAR(50);
+
+		SET @at_desc = (
+			SELECT AT.AT_Desc FROM dbo.Alarm_Templates AS AT WHERE AT.AT_Id = @param_at_id);
+
+		IF @at_desc IS NULL
+			THROW 50000, 'alarm template not found', 1;
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc, @var_desc = VB.Var_Desc
+		FROM dbo.Variables_Base AS VB
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE VB.Var_Id = @param_var_id;
+
+		IF @var_desc IS NULL
+			THROW 50000, 'variable not found', 1;
+
+		IF @param_eg_desc IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM dbo.Email_Groups AS EG WHERE EG.EG_Desc = @param_eg_desc)
+			THROW 50000, 'email group not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportAlarmTemplateData]
+				@AT_Desc = @at_desc,
+				@PL_Desc = @pl_desc,
+				@PU_Desc = @pu_desc,
+				@Var_Desc = @var_desc,
+				@EmailGroup = @param_eg_desc,
+				@User_Id = @param_user_id;
+		`
+
+	// The procedure only ever sets an email group, so changes are written directly.
+	queryUpdateAlarmTemplateVariable = `
+		SET XACT_ABORT ON;
+
+		DECLARE @eg_id INT;
+
+		IF @param_eg_desc IS NOT NULL
+		BEGIN
+			SET @eg_id = (SELECT EG.EG_Id FROM dbo.Email_Groups AS EG WHERE EG.EG_Desc = @param_eg_desc);
+
+			IF @eg_id IS NULL
+				THROW 50000, 'email group not found', 1;
+		END
+
+		UPDATE dbo.Alarm_Template_Var_Data SET
+			EG_Id = @eg_id
+		WHERE AT_Id = @param_at_id
+		AND Var_Id = @param_var_id;
+		`
+
+	queryDeleteAlarmTemplateVariable = `
+		DELETE FROM dbo.Alarm_Template_Var_Data
+		WHERE AT_Id = @param_at_id
+		AND Var_Id = @param_var_id;
+		`
+)
+
+// resourceAlarmTemplateVariable assigns one variable to an alarm template.
+func resourceAlarmTemplateVariable() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAlarmTemplateVariableCreate,
+		ReadContext:   resourceAlarmTemplateVariableRead,
+		UpdateContext: resourceAlarmTemplateVariableUpdate,
+		DeleteContext: resourceAlarmTemplateVariableDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceAlarmTemplateVariableImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"template_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"variable_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"email_group": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "EG_Desc from dbo.Email_Groups, notified when the alarm fires.",
+				ValidateFunc: validateVarchar50(),
+			},
+		},
+	}
+}
+
+// parseAlarmTemplateVariableId splits the "template/variable" ID.
+func parseAlarmTemplateVariableId(id string) (templateID, varID int64, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected alarm template variable ID %q", id)
+	}
+	if templateID, err = stringToInt64(parts[0]); err != nil {
+		return
+	}
+	varID, err = stringToInt64(parts[1])
+	return
+}
+
+func resourceAlarmTemplateVariableCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+	templateID := int64(d.Get("template_id").(int))
+	varID := int64(d.Get("variable_id").(int))
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var emailGroup sql.NullString
+		err := tx.QueryRowContext(ctx, queryGetAlarmTemplateVariable,
+			sql.Named("param_at_id", templateID),
+			sql.Named("param_var_id", varID),
+		).Scan(&emailGroup)
+		if err == nil {
+			return fmt.Errorf("variable %d is already on alarm template %d; import it instead", varID, templateID)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		var returnValue sql.NullInt64
+		_, err = tx.ExecContext(ctx, queryAddAlarmTemplateVariable,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_at_id", templateID),
+			sql.Named("param_var_id", varID),
+			sql.Named("param_eg_desc", stringToNullString(d.Get("email_group").(string))),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d", templateID, varID))
+	return resourceAlarmTemplateVariableRead(ctx, d, m)
+}
+
+func resourceAlarmTemplateVariableRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	templateID, varID, err := parseAlarmTemplateVariableId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var emailGroup sql.NullString
+	err = db.QueryRowContext(ctx, queryGetAlarmTemplateVariable,
+		sql.Named("param_at_id", templateID),
+		sql.Named("param_var_id", varID),
+	).Scan(&emailGroup)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("template_id", templateID)
+	d.Set("variable_id", varID)
+	d.Set("email_group", nullableStringToString(emailGroup))
+	return nil
+}
+
+func resourceAlarmTemplateVariableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	templateID, varID, err := parseAlarmTemplateVariableId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var emailGroup sql.NullString
+		err := tx.QueryRowContext(ctx, queryGetAlarmTemplateVariableForUpdate,
+			sql.Named("param_at_id", templateID),
+			sql.Named("param_var_id", varID),
+		).Scan(&emailGroup)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("variable %d is no longer on alarm template %d", varID, templateID)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "alarm template variable", map[string]interface{}{
+			"email_group": nullableStringToString(emailGroup),
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdateAlarmTemplateVariable,
+			sql.Named("param_at_id", templateID),
+			sql.Named("param_var_id", varID),
+			sql.Named("param_eg_desc", stringToNullString(d.Get("email_group").(string))),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceAlarmTemplateVariableRead(ctx, d, m)
+}
+
+func resourceAlarmTemplateVariableDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	templateID, varID, err := parseAlarmTemplateVariableId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteAlarmTemplateVariable,
+			sql.Named("param_at_id", templateID),
+			sql.Named("param_var_id", varID),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceAlarmTemplateVariableImport accepts "Template/Line/Unit/Variable".
+func resourceAlarmTemplateVariableImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Template", "Line", "Unit", "Variable")
+	if err != nil {
+		return nil, err
+	}
+
+	var templateID, varID int64
+	err = getDB(m).QueryRowContext(ctx, queryGetAlarmTemplateVariableIdsByPath,
+		sql.Named("param_at_desc", parts[0]),
+		sql.Named("param_pl_desc", parts[1]),
+		sql.Named("param_pu_desc", parts[2]),
+		sql.Named("param_var_desc", parts[3]),
+	).Scan(&templateID, &varID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("alarm template variable %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d", templateID, varID))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	// As with variable specs, a pair whose limits were all cleared counts as having none.
+	queryGetCentralSpec = `
+		SELECT	ASP.L_Entry, ASP.L_Reject, ASP.L_Warning, ASP.L_User, ASP.Target,
+				ASP.U_User, ASP.U_Warning, ASP.U_Reject, ASP.U_Entry,
+				ASP.L_Control, ASP.T_Control, ASP.U_Control, ASP.Test_Freq, ASP.Esignature_Level
+		FROM dbo.Active_Specs AS ASP
+		WHERE ASP.Spec_Id = @param_spec_id
+		AND ASP.Char_Id = @param_char_id
+		AND ASP.Expiration_Date IS NULL
+		AND COALESCE(ASP.L_Entry, ASP.L_Reject, ASP.L_Warning, ASP.L_User, ASP.Target,
+				ASP.U_User, ASP.U_Warning, ASP.U_Reject, ASP.U_Entry,
+				ASP.L_Control, ASP.T_Control, ASP.U_Control,
+				CAST(ASP.Test_Freq AS VARCHAR(25)), CAST(ASP.Esignature_Level AS VARCHAR(25))) IS NOT NULL;
+		`
+
+	queryGetCentralSpecForUpdate = `
+		SELECT	ASP.L_Entry, ASP.L_Reject, ASP.L_Warning, ASP.L_User, ASP.Target,
+				ASP.U_User, ASP.U_Warning, ASP.U_Reject, ASP.U_Entry,
+				ASP.L_Control, ASP.T_Control, ASP.U_Control, ASP.Test_Freq, ASP.Esignature_Level
+		FROM dbo.Active_Specs AS ASP WITH (UPDLOCK, HOLDLOCK)
+		WHERE ASP.Spec_Id = @param_spec_id
+		AND ASP.Char_Id = @param_char_id
+		AND ASP.Expiration_Date IS NULL
+		AND COALESCE(ASP.L_Entry, ASP.L_Reject, ASP.L_Warning, ASP.L_User, ASP.Target,
+				ASP.U_User, ASP.U_Warning, ASP.U_Reject, ASP.U_Entry,
+				ASP.L_Control, ASP.T_Control, ASP.U_Control,
+				CAST(ASP.Test_Freq AS VARCHAR(25)), CAST(ASP.Esignature_Level AS VARCHAR(25))) IS NOT NULL;
+		`
+
+	queryGetCentralSpecIdsByPath = `
+		SELECT S.Spec_Id, C.Char_Id
+		FROM dbo.Product_Properties AS PP
+		JOIN dbo.Specifications AS S ON S.Prop_Id = PP.Prop_Id
+		JOIN dbo.Characteristics AS C ON C.Prop_Id = PP.Prop_Id
+		WHERE PP.Prop_Desc = @param_prop_desc
+		AND S.Spec_Desc = @param_spec_desc
+		AND C.Char_Desc = @param_char_desc;
+		`
+
+	// spEM_IEImportCentralSpecs raises a transaction for one specification and characteristic.
+	// Passing every limit as NULL clears the pair's limits.
+	queryImportCentralSpec = `
+		SET XACT_ABORT ON;
+
+		DECLARE @prop_desc			NVARCHAR(80),
+				@spec_desc			NVARCHAR(50),
+				@char_desc			NVARCHAR(500);
+
+		SELECT	@prop_desc = PP.Prop_Desc, @spec_desc = S.Spec_Desc
+		FROM dbo.Specifications AS S
+		JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = S.Prop_Id
+		WHERE S.Spec_Id = @param_spec_id;
+
+		IF @spec_desc IS NULL
+			THROW 50000, 'specification not found', 1;
+
+		SET @char_desc = (
+			SELECT C.Char_Desc FROM dbo.Characteristics AS C
+			JOIN dbo.Specifications AS S ON S.Prop_Id = C.Prop_Id
+			WHERE C.Char_Id = @param_char_id AND S.Spec_Id = @param_spec_id);
+
+		IF @char_desc IS NULL
+			THROW 50000, 'characteristic not found under the specification''s property', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportCentralSpecs]
+				@Prop_Desc = @prop_desc,
+				@Spec_Desc = @spec_desc,
+				@Char_Desc = @char_desc,
+				@L_Entry = @p_l_entry,
+				@L_Reject = @p_l_reject,
+				@L_Warning = @p_l_warning,
+				@L_User = @p_l_user,
+				@U_User = @p_u_user,
+				@U_Warning = @p_u_warning,
+				@U_Reject = @p_u_reject,
+				@U_Entry = @p_u_entry,
+				@Test_Freq_Str = @p_test_frequency,
+				@ESigLevel = @p_esignature_level,
+				@L_Control = @p_l_control,
+				@T_Control = @p_t_control,
+				@U_Control = @p_u_control,
+				@Trans_Id = NULL,
+				@User_Id = @param_user_id;
+
+		-- spEM_IEImportCentralSpecs takes no target, so it is written to the active row the
+		-- procedure just left behind.
+		IF @return_value = 0
+		BEGIN
+			UPDATE dbo.Active_Specs SET
+				Target = @p_target
+			WHERE Spec_Id = @param_spec_id
+			AND Char_Id = @param_char_id
+			AND Expiration_Date IS NULL;
+
+			IF @@ROWCOUNT = 0 AND @p_target IS NOT NULL
+				THROW 50000, 'no active central spec to set the target on; set another limit, test_frequency or esignature_level as well', 1;
+		END
+		`
+)
+
+// resourceCentralSpec sets the central limits of a specification for one characteristic.
+func resourceCentralSpec() *schema.Resource {
+	s := specLimitsSchema()
+	s["specification_id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Required: true,
+		ForceNew: true,
+	}
+	s["characteristic_id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Required: true,
+		ForceNew: true,
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceCentralSpecCreate,
+		ReadContext:   resourceCentralSpecRead,
+		UpdateContext: resourceCentralSpecUpdate,
+		DeleteContext: resourceCentralSpecDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCentralSpecImport,
+		},
+		CustomizeDiff: resourceCentralSpecCustomizeDiff,
+		Schema:        s,
+	}
+}
+
+// resourceCentralSpecCustomizeDiff checks the limit order and that something is set at all;
+// with nothing set the pair has no active spec and would vanish from state after apply.
+func resourceCentralSpecCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	limits := map[string]interface{}{}
+	allKnown := true
+	for _, field := range append([]string{"test_frequency", "esignature_level"}, specLimitFields...) {
+		limits[field] = d.Get(field)
+		allKnown = allKnown && d.NewValueKnown(field)
+	}
+	if allKnown && !hasSpecLimits(limits) {
+		return fmt.Errorf("central spec needs at least one limit, test_frequency or esignature_level; destroy it to clear the limits")
+	}
+	return validateSpecLimitOrder("central spec limits", limits)
+}
+
+func centralSpecLimits(d *schema.ResourceData) map[string]interface{} {
+	limits := map[string]interface{}{
+		"test_frequency":   d.Get("test_frequency"),
+		"esignature_level": d.Get("esignature_level"),
+	}
+	for _, field := range specLimitFields {
+		limits[field] = d.Get(field)
+	}
+	return limits
+}
+
+// parseCentralSpecId splits the "spec/characteristic" ID.
+func parseCentralSpecId(id string) (specID, charID int64, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected central spec ID %q", id)
+	}
+	if specID, err = stringToInt64(parts[0]); err != nil {
+		return
+	}
+	charID, err = stringToInt64(parts[1])
+	return
+}
+
+func execImportCentralSpec(ctx context.Context, tx *sql.Tx, specID, charID int64, limits map[string]interface{}) error {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	args := append(specLimitArgs(limits),
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_spec_id", specID),
+		sql.Named("param_char_id", charID),
+		sql.Named("param_user_id", userId),
+	)
+	if _, err := tx.ExecContext(ctx, queryImportCentralSpec, args...); err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceCentralSpecCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	specID := int64(d.Get("specification_id").(int))
+	charID := int64(d.Get("characteristic_id").(int))
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := scanSpecLimits(tx.QueryRowContext(ctx, queryGetCentralSpecForUpdate,
+			sql.Named("param_spec_id", specID),
+			sql.Named("param_char_id", charID),
+		))
+		if err == nil {
+			return fmt.Errorf("specification %d already has limits for characteristic %d; import them instead", specID, charID)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+		if err := execImportCentralSpec(ctx, tx, specID, charID, centralSpecLimits(d)); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d", specID, charID))
+	return resourceCentralSpecRead(ctx, d, m)
+}
+
+func resourceCentralSpecRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	specID, charID, err := parseCentralSpecId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	limits, err := scanSpecLimits(db.QueryRowContext(ctx, queryGetCentralSpec,
+		sql.Named("param_spec_id", specID),
+		sql.Named("param_char_id", charID),
+	))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("specification_id", specID)
+	d.Set("characteristic_id", charID)
+	for key, value := range limits {
+		d.Set(key, value)
+	}
+	return nil
+}
+
+func resourceCentralSpecUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	specID, charID, err := parseCentralSpecId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanSpecLimits(tx.QueryRowContext(ctx, queryGetCentralSpecForUpdate,
+			sql.Named("param_spec_id", specID),
+			sql.Named("param_char_id", charID),
+		))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("central spec %s no longer exists", d.Id())
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "central spec", current); err != nil {
+			return err
+		}
+		return execImportCentralSpec(ctx, tx, specID, charID, centralSpecLimits(d))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceCentralSpecRead(ctx, d, m)
+}
+
+func resourceCentralSpecDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	specID, charID, err := parseCentralSpecId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		return execImportCentralSpec(ctx, tx, specID, charID, map[string]interface{}{})
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceCentralSpecImport accepts "Property/Spec/Characteristic".
+func resourceCentralSpecImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Property", "Spec", "Characteristic")
+	if err != nil {
+		return nil, err
+	}
+
+	var specID, charID int64
+	err = getDB(m).QueryRowContext(ctx, queryGetCentralSpecIdsByPath,
+		sql.Named("param_prop_desc", parts[0]),
+		sql.Named("param_spec_desc", parts[1]),
+		sql.Named("param_char_desc", parts[2]),
+	).Scan(&specID, &charID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("central spec %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d", specID, charID))
+	return []*schema.ResourceData{d}, nil
+}
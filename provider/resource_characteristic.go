@@ -0,0 +1,323 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type Characteristic struct {
+	Char_Id      int64
+	Prop_Id      int64
+	Description  string
+	Parent_Id    int64
+	ExternalLink string
+	ExtendedInfo string
+}
+
+const (
+	queryGetCharacteristic = `
+		SELECT	C.Char_Id, C.Prop_Id, C.Char_Desc, C.Derived_From_Parent, C.External_Link,
+				C.Extended_Info
+		FROM dbo.Characteristics AS C
+		WHERE C.Char_Id = @param_char_id;
+		`
+
+	queryGetCharacteristicForUpdate = `
+		SELECT	C.Char_Id, C.Prop_Id, C.Char_Desc, C.Derived_From_Parent, C.External_Link,
+				C.Extended_Info
+		FROM dbo.Characteristics AS C WITH (UPDLOCK, HOLDLOCK)
+		WHERE C.Char_Id = @param_char_id;
+		`
+
+	queryGetCharacteristicIdByPath = `
+		SELECT C.Char_Id
+		FROM dbo.Characteristics AS C
+		JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = C.Prop_Id
+		WHERE PP.Prop_Desc = @param_prop_desc
+		AND C.Char_Desc = @param_char_desc;
+		`
+
+	queryCreateCharacteristic = `
+		SET XACT_ABORT ON;
+
+		DECLARE @prop_desc			NVARCHAR(80),
+				@parent_desc		NVARCHAR(500);
+
+		SET @prop_desc = (
+			SELECT PP.Prop_Desc FROM dbo.Product_Properties AS PP WHERE PP.Prop_Id = @param_prop_id);
+
+		IF @prop_desc IS NULL
+			THROW 50000, 'product property not found', 1;
+
+		IF @param_parent_id IS NOT NULL
+		BEGIN
+			SET @parent_desc = (
+				SELECT C.Char_Desc FROM dbo.Characteristics AS C
+				WHERE C.Char_Id = @param_parent_id AND C.Prop_Id = @param_prop_id);
+
+			IF @parent_desc IS NULL
+				THROW 50000, 'parent characteristic not found under this property', 1;
+		END
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Characteristics AS C
+			WHERE C.Prop_Id = @param_prop_id AND C.Char_Desc = @param_char_desc)
+			THROW 50000, 'a characteristic with this description already exists; import it instead', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportCharacteristics]
+				@Prop_Desc = @prop_desc,
+				@Char_Desc = @param_char_desc,
+				@Parent_Char_Desc = @parent_desc,
+				@ExtLink = @param_ext_link,
+				@ExtInfo = @param_ext_info,
+				@User_Id = @param_user_id,
+				@Trans_Id = NULL;
+
+		SET @out_Char_Id = (
+			SELECT C.Char_Id FROM dbo.Characteristics AS C
+			WHERE C.Prop_Id = @param_prop_id AND C.Char_Desc = @param_char_desc);
+		`
+
+	queryUpdateCharacteristic = `
+		SET XACT_ABORT ON;
+
+		IF @param_parent_id IS NOT NULL AND NOT EXISTS (
+			SELECT 1 FROM dbo.Characteristics AS C
+			WHERE C.Char_Id = @param_parent_id AND C.Prop_Id = @param_prop_id AND C.Char_Id <> @param_char_id)
+			THROW 50000, 'parent characteristic not found under this property', 1;
+
+		UPDATE dbo.Characteristics SET
+			Char_Desc = @param_char_desc,
+			Derived_From_Parent = @param_parent_id,
+			External_Link = @param_ext_link,
+			Extended_Info = @param_ext_info
+		WHERE Char_Id = @param_char_id;
+		`
+
+	queryDeleteCharacteristic = `
+		EXEC	@return_value = [dbo].[spEM_DropChar]
+				@Char_Id = @param_char_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceCharacteristic() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCharacteristicCreate,
+		ReadContext:   resourceCharacteristicRead,
+		UpdateContext: resourceCharacteristicUpdate,
+		DeleteContext: resourceCharacteristicDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCharacteristicImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"characteristic_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"property_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Char_Desc, unique within the property.",
+				ValidateFunc: validation.StringLenBetween(1, 50),
+			},
+			"parent_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Characteristic under the same property whose specifications this one inherits.",
+			},
+			"external_link": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"extended_info": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+		},
+	}
+}
+
+func scanCharacteristic(row interface{ Scan(...interface{}) error }) (*Characteristic, error) {
+	var characteristic Characteristic
+	var propID, parentID sql.NullInt64
+	var description, externalLink, extendedInfo sql.NullString
+	if err := row.Scan(&characteristic.Char_Id, &propID, &description, &parentID, &externalLink,
+		&extendedInfo); err != nil {
+		return nil, err
+	}
+	characteristic.Prop_Id = nullableIdToInt64(propID)
+	characteristic.Description = nullableStringToString(description)
+	characteristic.Parent_Id = nullableIdToInt64(parentID)
+	characteristic.ExternalLink = nullableStringToString(externalLink)
+	characteristic.ExtendedInfo = nullableStringToString(extendedInfo)
+	return &characteristic, nil
+}
+
+func resourceCharacteristicCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	var outCharID sql.NullInt64
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateCharacteristic,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_prop_id", int64(d.Get("property_id").(int))),
+			sql.Named("param_char_desc", d.Get("description").(string)),
+			sql.Named("param_parent_id", idToNullInt64(int64(d.Get("parent_id").(int)))),
+			sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+			sql.Named("param_ext_info", stringToNullString(d.Get("extended_info").(string))),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_Char_Id", sql.Out{Dest: &outCharID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 || !outCharID.Valid {
+			return fmt.Errorf(
+				"stored procedure returned failure status: return_value=%v, outCharID.Valid=%v",
+				returnValue.Int64, outCharID.Valid)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("characteristic_id", int(outCharID.Int64))
+	d.SetId(int64ToString(outCharID.Int64))
+	return resourceCharacteristicRead(ctx, d, m)
+}
+
+func resourceCharacteristicRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	characteristic, err := scanCharacteristic(db.QueryRowContext(ctx, queryGetCharacteristic, sql.Named("param_char_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("characteristic_id", characteristic.Char_Id)
+	d.Set("property_id", characteristic.Prop_Id)
+	d.Set("description", characteristic.Description)
+	d.Set("parent_id", characteristic.Parent_Id)
+	d.Set("external_link", characteristic.ExternalLink)
+	d.Set("extended_info", characteristic.ExtendedInfo)
+	return nil
+}
+
+func resourceCharacteristicUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanCharacteristic(tx.QueryRowContext(ctx, queryGetCharacteristicForUpdate, sql.Named("param_char_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("characteristic %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "characteristic", map[string]interface{}{
+			"description":   current.Description,
+			"parent_id":     current.Parent_Id,
+			"external_link": current.ExternalLink,
+			"extended_info": current.ExtendedInfo,
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdateCharacteristic,
+			sql.Named("param_char_id", id),
+			sql.Named("param_prop_id", current.Prop_Id),
+			sql.Named("param_char_desc", d.Get("description").(string)),
+			sql.Named("param_parent_id", idToNullInt64(int64(d.Get("parent_id").(int)))),
+			sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+			sql.Named("param_ext_info", stringToNullString(d.Get("extended_info").(string))),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceCharacteristicRead(ctx, d, m)
+}
+
+func resourceCharacteristicDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteCharacteristic,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_char_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceCharacteristicImport accepts "Property/Characteristic".
+func resourceCharacteristicImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Property", "Characteristic")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetCharacteristicIdByPath,
+		sql.Named("param_prop_desc", parts[0]),
+		sql.Named("param_char_desc", parts[1]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("characteristic %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetCharacteristicGroup = `
+		SELECT CG.Prop_Id, CG.Characteristic_Grp_Desc
+		FROM dbo.Characteristic_Groups AS CG
+		WHERE CG.Characteristic_Grp_Id = @param_group_id;
+		`
+
+	queryGetCharacteristicGroupForUpdate = `
+		SELECT CG.Prop_Id, CG.Characteristic_Grp_Desc
+		FROM dbo.Characteristic_Groups AS CG WITH (UPDLOCK, HOLDLOCK)
+		WHERE CG.Characteristic_Grp_Id = @param_group_id;
+		`
+
+	queryGetCharacteristicGroupIdByPath = `
+		SELECT CG.Characteristic_Grp_Id
+		FROM dbo.Characteristic_Groups AS CG
+		JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = CG.Prop_Id
+		WHERE PP.Prop_Desc = @param_prop_desc
+		AND CG.Characteristic_Grp_Desc = @param_group_desc;
+		`
+
+	queryLoadCharacteristicGroupMembers = `
+		SELECT CGD.Char_Id
+		FROM dbo.Characteristic_Group_Data AS CGD
+		WHERE CGD.Characteristic_Grp_Id = @param_group_id
+		ORDER BY CGD.Char_Id;
+		`
+
+	// spEM_IEImportCharacteristicGroups creates the group on first use and adds one
+	// characteristic to it.
+	queryAddCharacteristicGroupMember = `
+		SET XACT_ABORT ON;
+
+		DECLARE @prop_desc			NVARCHAR(100),
+				@char_desc			NVARCHAR(500),
+				@msg				NVARCHAR(2048);
+
+		SET @prop_desc = (
+			SELECT PP.Prop_Desc FROM dbo.Product_Properties AS PP WHERE PP.Prop_Id = @param_prop_id);
+
+		IF @prop_desc IS NULL
+			THROW 50000, 'product property not found', 1;
+
+		SET @char_desc = (
+			SELECT C.Char_Desc FROM dbo.Characteristics AS C
+			WHERE C.Char_Id = @param_char_id AND C.Prop_Id = @param_prop_id);
+
+		IF @char_desc IS NULL
+		BEGIN
+			SET @msg = N'characteristic ' + CAST(@param_char_id AS NVARCHAR(20)) + N' not found under this property';
+			THROW 50000, @msg, 1;
+		END
+
+		EXEC	@return_value = [dbo].[spEM_IEImportCharacteristicGroups]
+				@Prop_Desc = @prop_desc,
+				@Characteristic_Grp_Desc = @param_group_desc,
+				@Char_Desc = @char_desc,
+				@User_Id = @param_user_id;
+		`
+
+	// spEM_DropCharGroupData is the PA Administrator's removal, so it is audited the same way.
+	queryRemoveCharacteristicGroupMember = `
+		EXEC	@return_value = [dbo].[spEM_DropCharGroupData]
+				@Characteristic_Grp_Id = @param_group_id,
+				@Char_Id = @param_char_id,
+				@User_Id = @param_user_id;
+		`
+
+	queryUpdateCharacteristicGroup = `
+		UPDATE dbo.Characteristic_Groups SET
+			Characteristic_Grp_Desc = @param_group_desc
+		WHERE Characteristic_Grp_Id = @param_group_id;
+		`
+
+	// spEM_DropCharGroup removes the group together with its membership rows.
+	queryDeleteCharacteristicGroup = `
+		EXEC	@return_value = [dbo].[spEM_DropCharGroup]
+				@Characteristic_Grp_Id = @param_group_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceCharacteristicGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCharacteristicGroupCreate,
+		ReadContext:   resourceCharacteristicGroupRead,
+		UpdateContext: resourceCharacteristicGroupUpdate,
+		DeleteContext: resourceCharacteristicGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCharacteristicGroupImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"property_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Characteristic_Grp_Desc.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"characteristic_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Every characteristic in the group, all under property_id. Members added outside Terraform are removed.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+func loadCharacteristicGroupMembers(rows *sql.Rows, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]interface{}, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, int(id))
+	}
+	return ids, rows.Err()
+}
+
+// syncCharacteristicGroupMembers adds and removes members so the group matches desired exactly.
+// groupID is 0 while the group does not exist yet.
+func syncCharacteristicGroupMembers(ctx context.Context, tx *sql.Tx, groupID, propID int64, groupDesc string, desired *schema.Set) error {
+	userId := 1
+
+	ids, err := loadCharacteristicGroupMembers(tx.QueryContext(ctx, queryLoadCharacteristicGroupMembers, sql.Named("param_group_id", groupID)))
+	if err != nil {
+		return err
+	}
+	current := schema.NewSet(schema.HashInt, ids)
+
+	for _, id := range current.Difference(desired).List() {
+		var returnValue sql.NullInt64
+		_, err := tx.ExecContext(ctx, queryRemoveCharacteristicGroupMember,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_group_id", groupID),
+			sql.Named("param_char_id", int64(id.(int))),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to remove characteristic %d from group %q: %w", id, groupDesc, err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status %d removing characteristic %d", returnValue.Int64, id)
+		}
+	}
+
+	for _, id := range desired.Difference(current).List() {
+		var returnValue sql.NullInt64
+		_, err := tx.ExecContext(ctx, queryAddCharacteristicGroupMember,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_prop_id", propID),
+			sql.Named("param_group_desc", groupDesc),
+			sql.Named("param_char_id", int64(id.(int))),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add characteristic %d to group %q: %w", id, groupDesc, err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status %d adding characteristic %d", returnValue.Int64, id)
+		}
+	}
+	return nil
+}
+
+func resourceCharacteristicGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	propID := int64(d.Get("property_id").(int))
+	description := d.Get("description").(string)
+
+	var groupID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var propDesc string
+		err := tx.QueryRowContext(ctx, queryGetProductPropertyDesc, sql.Named("param_prop_id", propID)).Scan(&propDesc)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product property %d not found", propID)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetCharacteristicGroupIdByPath,
+			sql.Named("param_prop_desc", propDesc),
+			sql.Named("param_group_desc", description),
+		).Scan(&groupID)
+		if err == nil {
+			return fmt.Errorf("characteristic group %q already exists; import it instead", description)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := syncCharacteristicGroupMembers(ctx, tx, 0, propID, description, d.Get("characteristic_ids").(*schema.Set)); err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetCharacteristicGroupIdByPath,
+			sql.Named("param_prop_desc", propDesc),
+			sql.Named("param_group_desc", description),
+		).Scan(&groupID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("characteristic group %q was not created", description)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("group_id", int(groupID))
+	d.SetId(int64ToString(groupID))
+	return resourceCharacteristicGroupRead(ctx, d, m)
+}
+
+func resourceCharacteristicGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var propID sql.NullInt64
+	var description sql.NullString
+	err = db.QueryRowContext(ctx, queryGetCharacteristicGroup, sql.Named("param_group_id", id)).Scan(&propID, &description)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ids, err := loadCharacteristicGroupMembers(db.QueryContext(ctx, queryLoadCharacteristicGroupMembers, sql.Named("param_group_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("group_id", id)
+	d.Set("property_id", nullableIdToInt64(propID))
+	d.Set("description", nullableStringToString(description))
+	d.Set("characteristic_ids", ids)
+	return nil
+}
+
+func resourceCharacteristicGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	description := d.Get("description").(string)
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var propID sql.NullInt64
+		var current sql.NullString
+		err := tx.QueryRowContext(ctx, queryGetCharacteristicGroupForUpdate, sql.Named("param_group_id", id)).Scan(&propID, &current)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("characteristic group %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "characteristic group", map[string]interface{}{
+			"description": nullableStringToString(current),
+		}); err != nil {
+			return err
+		}
+
+		if d.HasChange("description") {
+			_, err = tx.ExecContext(ctx, queryUpdateCharacteristicGroup,
+				sql.Named("param_group_id", id),
+				sql.Named("param_group_desc", description),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return syncCharacteristicGroupMembers(ctx, tx, id, nullableIdToInt64(propID), description, d.Get("characteristic_ids").(*schema.Set))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceCharacteristicGroupRead(ctx, d, m)
+}
+
+func resourceCharacteristicGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	userId := 1
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var returnValue sql.NullInt64
+		_, err := tx.ExecContext(ctx, queryDeleteCharacteristicGroup,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_group_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceCharacteristicGroupImport accepts "Property/Group".
+func resourceCharacteristicGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Property", "Group")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetCharacteristicGroupIdByPath,
+		sql.Named("param_prop_desc", parts[0]),
+		sql.Named("param_group_desc", parts[1]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("characteristic group %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
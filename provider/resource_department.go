@@ -31,6 +31,8 @@ const (
 		ORDER BY Dept_Id DESC;`
 
 	queryCreateDepartment = `
+		SET XACT_ABORT ON;
+
 		EXEC @return_value = [dbo].[spEM_CreateDepartment]
 		    @Description = @param_desc,
 		    @User_Id = @param_user_id,
@@ -49,6 +51,11 @@ const (
 		WHERE Dept_Id = @out_deptId;
 		`
 
+	queryGetDepartmentForUpdate = `
+		SELECT Dept_Desc, Extended_Info, Time_Zone, Tag
+		FROM dbo.Departments_Base WITH (UPDLOCK, HOLDLOCK)
+		WHERE Dept_Id = @param_deptId;`
+
 	queryUpdateDepartment = `
 		UPDATE dbo.Departments_Base SET
 			Dept_Desc = ISNULL(@param_desc, Dept_Desc),
@@ -66,6 +73,7 @@ func resourceDepartment() *schema.Resource {
 		ReadContext:   resourceDepartmentRead,
 		UpdateContext: resourceDepartmentUpdate,
 		DeleteContext: resourceDepartmentDelete,
+		CustomizeDiff: customizeDeletionProtection,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -92,6 +100,7 @@ func resourceDepartment() *schema.Resource {
                 Type:     schema.TypeString,
                 Optional: true,
             },
+            "deletion_protection": deletionProtectionSchema(),
         },
 	}
 }
@@ -128,8 +137,6 @@ func loadDepartmentsCache(ctx context.Context, m interface{}) error {
 }
 
 func resourceDepartmentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	db := getDB(m)
-
 	if err := loadDepartmentsCache(ctx, m); err != nil {
 		return diag.FromErr(err)
 	}
@@ -147,20 +154,28 @@ func resourceDepartmentCreate(ctx context.Context, d *schema.ResourceData, m int
 	tag = stringToNullString(d.Get("tag").(string))
 	userId := 1	
 
-	_, err := db.ExecContext(ctx, queryCreateDepartment,
-		sql.Named("return_value", sql.Out{Dest: &returnValue}),
-		sql.Named("param_desc", description),
-		sql.Named("param_user_id", userId),
-		sql.Named("out_deptId", sql.Out{Dest: &deptID}),
-		sql.Named("param_ext_info", extendedInfo),
-		sql.Named("param_tz", timeZone),
-		sql.Named("param_tag", tag),
-	)
+	// The department is created and then configured in one transaction, so a failing
+	// UPDATE rolls back spEM_CreateDepartment instead of leaving an untracked department.
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateDepartment,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_desc", description),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_deptId", sql.Out{Dest: &deptID}),
+			sql.Named("param_ext_info", extendedInfo),
+			sql.Named("param_tz", timeZone),
+			sql.Named("param_tag", tag),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue != 0 || deptID == 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d or null ID", returnValue)
+		}
+		return nil
+	})
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to execute create logic: %w", err))
-	}
-	if returnValue != 0 || deptID == 0 {
-		return diag.FromErr(fmt.Errorf("stored procedure returned failure status: %d or null ID", returnValue))
+		return diag.FromErr(err)
 	}
 
 	cachedDepartments[deptID] = &Department{
@@ -197,8 +212,9 @@ func resourceDepartmentRead(ctx context.Context, d *schema.ResourceData, m inter
 }
 
 func resourceDepartmentUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	db := getDB(m)
-
+	if onlyProviderAttributesChanged(d) {
+		return nil
+	}
 	id := int64(d.Get("dept_id").(int))
 	description := d.Get("description").(string)
 	extendedInfo := d.Get("extended_info").(string)
@@ -206,27 +222,58 @@ func resourceDepartmentUpdate(ctx context.Context, d *schema.ResourceData, m int
 	tag := d.Get("tag").(string)
 	userId := 1
 
-	_, err := db.ExecContext(ctx, queryUpdateDepartment,
-		sql.Named("param_deptId", id),
-		sql.Named("param_desc", stringToNullString(description)),
-		sql.Named("param_user_id", userId),
-		sql.Named("param_ext_info", stringToNullString(extendedInfo)),
-		sql.Named("param_tz", stringToNullString(timeZone)),
-		sql.Named("param_tag", stringToNullString(tag)),
-	)
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var curDescription, curExtendedInfo, curTimeZone, curTag sql.NullString
+		err := tx.QueryRowContext(ctx, queryGetDepartmentForUpdate, sql.Named("param_deptId", id)).
+			Scan(&curDescription, &curExtendedInfo, &curTimeZone, &curTag)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("department %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "department", map[string]interface{}{
+			"description":   nullableStringToString(curDescription),
+			"extended_info": nullableStringToString(curExtendedInfo),
+			"time_zone":     nullableStringToString(curTimeZone),
+			"tag":           nullableStringToString(curTag),
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdateDepartment,
+			sql.Named("param_deptId", id),
+			sql.Named("param_desc", stringToNullString(description)),
+			sql.Named("param_user_id", userId),
+			sql.Named("param_ext_info", stringToNullString(extendedInfo)),
+			sql.Named("param_tz", stringToNullString(timeZone)),
+			sql.Named("param_tag", stringToNullString(tag)),
+		)
+		return err
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	if dept, ok := cachedDepartments[id]; ok {
+		dept.Description = description
+		dept.ExtendedInfo = extendedInfo
+		dept.TimeZone = timeZone
+		dept.Tag = tag
+	}
 	return resourceDepartmentRead(ctx, d, m)
 }
 
 func resourceDepartmentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	db := getDB(m)
-
+	if err := checkDeletionProtection(d, "department"); err != nil {
+		return diag.FromErr(err)
+	}
 	id := int64(d.Get("dept_id").(int))
 
-	_, err := db.ExecContext(ctx, queryDeleteDepartment, sql.Named("param_deptId", id))
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteDepartment, sql.Named("param_deptId", id))
+		return err
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -0,0 +1,1046 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// displayField ties one pa_display attribute to its spEM_IEImportDisplays parameter and to
+// the expression queryGetDisplay reads it back with, the same way variableField does.
+type displayField variableField
+
+func (f displayField) procValue(d *schema.ResourceData) sql.NullString {
+	return variableField(f).procValue(d)
+}
+
+// displaySpecColumns are the sDisplaySpec<Name> flags, each stored in Sheets.Display_Spec_<Name>.
+var displaySpecColumns = []string{
+	"Win", "Column", "Value", "Target", "Lower", "Upper", "Comment", "Status", "Action", "Reason",
+	"User", "Time", "Duration", "Shift", "Crew", "Product", "Order", "Batch", "Lot", "Unit",
+	"Grade", "Sublot", "Pallet", "Container", "Label", "Location", "Destination", "Carrier",
+	"Shipment", "Customer", "Supplier", "Equipment", "Tool", "Reference", "Version",
+	"Description", "SPC",
+}
+
+var displayFields = buildDisplayFields()
+
+func buildDisplayFields() []displayField {
+	fields := []displayField{
+		{"group", "Sheet_Group_Desc", "SG.Sheet_Group_Desc", &schema.Schema{Type: schema.TypeString, Required: true, Description: "Sheet_Group_Desc from dbo.Sheet_Groups."}},
+		{"sheet_type", "Sheet_Type_Desc", "STY.Sheet_Type_Desc", &schema.Schema{Type: schema.TypeString, Required: true, ForceNew: true, Description: "Sheet_Type_Desc from dbo.Sheet_Type."}},
+		{"event_prompt", "Event_Prompt", "S.Event_Prompt", variableString(validateVarchar50())},
+		{"interval", "sInterval", "S.Interval", variableInt()},
+		{"offset", "sOffset", "S.Offset", variableInt()},
+		{"initial_count", "sInitialCount", "S.Initial_Count", variableInt()},
+		{"maximum_count", "sMaximumCount", "S.Maximum_Count", variableInt()},
+		{"max_edit_hours", "sMaxEditHours", "S.Max_Edit_Hours", variableInt()},
+		{"row_headers", "sRowHeaders", "S.Row_Headers", variableBool()},
+		{"column_headers", "sColumnHeaders", "S.Column_Headers", variableBool()},
+		{"row_numbering", "sRowNumbering", "S.Row_Numbering", variableBool()},
+		{"column_numbering", "sColumnNumbering", "S.Column_Numbering", variableBool()},
+	}
+	for _, name := range displaySpecColumns {
+		attribute := "display_spec_" + strings.ToLower(name)
+		if name == "Win" {
+			attribute = "display_spec_window"
+		}
+		fields = append(fields, displayField{attribute, "sDisplaySpec" + name, "S.Display_Spec_" + name, variableBool()})
+	}
+	return fields
+}
+
+const (
+	queryGetDisplayFrom = `
+		FROM dbo.Sheets AS S
+		LEFT JOIN dbo.Sheet_Groups AS SG ON SG.Sheet_Group_Id = S.Sheet_Group_Id
+		LEFT JOIN dbo.Sheet_Type AS STY ON STY.Sheet_Type_Id = S.Sheet_Type
+		`
+
+	queryGetDisplayIdByName = `
+		SELECT S.Sheet_Id
+		FROM dbo.Sheets AS S
+		WHERE S.Sheet_Desc = @param_sheet_desc;
+		`
+
+	queryRenameDisplay = `
+		UPDATE dbo.Sheets SET
+			Sheet_Desc = @param_sheet_desc
+		WHERE Sheet_Id = @param_sheet_id;
+		`
+
+	queryDeleteDisplay = `
+		SET XACT_ABORT ON;
+
+		DELETE FROM dbo.Sheet_Display_Options WHERE Sheet_Id = @param_sheet_id;
+		DELETE FROM dbo.Sheet_Paths WHERE Sheet_Id = @param_sheet_id;
+		DELETE FROM dbo.Sheet_Plots WHERE Sheet_Id = @param_sheet_id;
+		DELETE FROM dbo.Sheet_Unit WHERE Sheet_Id = @param_sheet_id;
+		DELETE FROM dbo.Sheet_Variables WHERE Sheet_Id = @param_sheet_id;
+		DELETE FROM dbo.Sheets WHERE Sheet_Id = @param_sheet_id;
+		`
+
+	queryLoadDisplayVariables = `
+		SELECT	SV.Var_Id, SV.Title, SV.Activity_Order, SV.Activity_Alias, SV.Target_Duration,
+				SV.Execution_Start_Duration, SV.AutoComplete_Duration, SV.External_URL_link
+		FROM dbo.Sheet_Variables AS SV
+		WHERE SV.Sheet_Id = @param_sheet_id
+		ORDER BY SV.Var_Order;
+		`
+
+	// spEM_IEImportDisplayVariables adds one column; a row with only a title is a title row.
+	queryAddDisplayVariable = `
+		SET XACT_ABORT ON;
+
+		DECLARE @sheet_desc			NVARCHAR(100),
+				@pl_desc			NVARCHAR(100),
+				@pu_desc			NVARCHAR(100),
+				@var_desc			NVARCHAR(100);
+
+		SET @sheet_desc = (SELECT S.Sheet_Desc FROM dbo.Sheets AS S WHERE S.Sheet_Id = @param_sheet_id);
+
+		IF @sheet_desc IS NULL
+			THROW 50000, 'display not found', 1;
+
+		IF @param_var_id IS NOT NULL
+		BEGIN
+			SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc, @var_desc = VB.Var_Desc
+			FROM dbo.Variables_Base AS VB
+			JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+			JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+			WHERE VB.Var_Id = @param_var_id;
+
+			IF @var_desc IS NULL
+				THROW 50000, 'variable not found', 1;
+		END
+
+		EXEC	@return_value = [dbo].[spEM_IEImportDisplayVariables]
+				@Sheet_Desc = @sheet_desc,
+				@PL_Desc = @pl_desc,
+				@PU_Desc = @pu_desc,
+				@Var_Desc = @var_desc,
+				@Title = @param_title,
+				@Var_Order = @param_var_order,
+				@Activity_Order = @param_activity_order,
+				@Execution_Start_Duration = @param_execution_start_duration,
+				@Target_Duration = @param_target_duration,
+				@Activity_Alias = @param_activity_alias,
+				@AutoComplete_Duration = @param_autocomplete_duration,
+				@External_URL_link = @param_external_url,
+				@Open_URL_Configuration = NULL,
+				@Password = NULL,
+				@User_Login = NULL,
+				@User_Id = @param_user_id;
+		`
+
+	queryClearDisplayVariables = `
+		DELETE FROM dbo.Sheet_Variables
+		WHERE Sheet_Id = @param_sheet_id
+		AND Var_Order >= @param_from_order;
+		`
+
+	queryLoadDisplayUnits = `
+		SELECT SU.PU_Id
+		FROM dbo.Sheet_Unit AS SU
+		WHERE SU.Sheet_Id = @param_sheet_id;
+		`
+
+	// spEM_IEImportDisplayUnits adds one unit to the display.
+	queryAddDisplayUnit = `
+		SET XACT_ABORT ON;
+
+		DECLARE @sheet_desc			NVARCHAR(100),
+				@pl_desc			NVARCHAR(100),
+				@pu_desc			NVARCHAR(100);
+
+		SET @sheet_desc = (SELECT S.Sheet_Desc FROM dbo.Sheets AS S WHERE S.Sheet_Id = @param_sheet_id);
+
+		IF @sheet_desc IS NULL
+			THROW 50000, 'display not found', 1;
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc
+		FROM dbo.Prod_Units_Base AS PUB
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+
+		IF @pu_desc IS NULL
+			THROW 50000, 'unit not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportDisplayUnits]
+				@SheetDesc = @sheet_desc,
+				@PlDesc = @pl_desc,
+				@PuDesc = @pu_desc,
+				@UserId = @param_user_id;
+		`
+
+	queryRemoveDisplayUnit = `
+		DELETE FROM dbo.Sheet_Unit
+		WHERE Sheet_Id = @param_sheet_id
+		AND PU_Id = @param_pu_id;
+		`
+
+	queryLoadDisplayPlots = `
+		SELECT	STT.SPC_Trend_Type_Desc, SP.Var_Id1, SP.Var_Id2, SP.Var_Id3, SP.Var_Id4, SP.Var_Id5
+		FROM dbo.Sheet_Plots AS SP
+		LEFT JOIN dbo.SPC_Trend_Types AS STT ON STT.SPC_Trend_Type_Id = SP.SPC_Trend_Type_Id
+		WHERE SP.Sheet_Id = @param_sheet_id
+		ORDER BY SP.Plot_Order;
+		`
+
+	queryClearDisplayPlots = `
+		DELETE FROM dbo.Sheet_Plots
+		WHERE Sheet_Id = @param_sheet_id
+		AND Plot_Order >= @param_from_order;
+		`
+
+	queryLoadDisplayPaths = `
+		SELECT PEP.Path_Code
+		FROM dbo.Sheet_Paths AS SPA
+		JOIN dbo.PrdExec_Paths AS PEP ON PEP.Path_Id = SPA.Path_Id
+		WHERE SPA.Sheet_Id = @param_sheet_id;
+		`
+
+	// spEM_IEImportDisplayPaths adds one execution path to the display.
+	queryAddDisplayPath = `
+		SET XACT_ABORT ON;
+
+		DECLARE @sheet_desc NVARCHAR(100);
+
+		SET @sheet_desc = (SELECT S.Sheet_Desc FROM dbo.Sheets AS S WHERE S.Sheet_Id = @param_sheet_id);
+
+		IF @sheet_desc IS NULL
+			THROW 50000, 'display not found', 1;
+
+		IF NOT EXISTS (SELECT 1 FROM dbo.PrdExec_Paths AS PEP WHERE PEP.Path_Code = @param_path_code)
+			THROW 50000, 'execution path not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportDisplayPaths]
+				@SheetDesc = @sheet_desc,
+				@PathCode = @param_path_code,
+				@UserId = @param_user_id;
+		`
+
+	queryRemoveDisplayPath = `
+		DELETE SPA
+		FROM dbo.Sheet_Paths AS SPA
+		JOIN dbo.PrdExec_Paths AS PEP ON PEP.Path_Id = SPA.Path_Id
+		WHERE SPA.Sheet_Id = @param_sheet_id
+		AND PEP.Path_Code = @param_path_code;
+		`
+
+	queryLoadDisplayOptions = `
+		SELECT DO.Display_Option_Desc, SDO.Value
+		FROM dbo.Sheet_Display_Options AS SDO
+		JOIN dbo.Display_Options AS DO ON DO.Display_Option_Id = SDO.Display_Option_Id
+		WHERE SDO.Sheet_Id = @param_sheet_id;
+		`
+
+	// spEM_IEImportDisplayOptions sets one option on the display.
+	querySetDisplayOption = `
+		SET XACT_ABORT ON;
+
+		DECLARE @sheet_desc NVARCHAR(100);
+
+		SET @sheet_desc = (SELECT S.Sheet_Desc FROM dbo.Sheets AS S WHERE S.Sheet_Id = @param_sheet_id);
+
+		IF @sheet_desc IS NULL
+			THROW 50000, 'display not found', 1;
+
+		IF NOT EXISTS (SELECT 1 FROM dbo.Display_Options AS DO WHERE DO.Display_Option_Desc = @param_option_desc)
+		BEGIN
+			DECLARE @msg NVARCHAR(2048);
+			SET @msg = CONCAT('display option not found: ', @param_option_desc);
+			THROW 50000, @msg, 1;
+		END
+
+		EXEC	@return_value = [dbo].[spEM_IEImportDisplayOptions]
+				@Sheet_Desc = @sheet_desc,
+				@Display_Option = @param_option_desc,
+				@Value = @param_option_value,
+				@User_Id = @param_user_id;
+		`
+
+	// Without its own row the option falls back to the sheet type's default.
+	queryResetDisplayOption = `
+		DELETE SDO
+		FROM dbo.Sheet_Display_Options AS SDO
+		JOIN dbo.Display_Options AS DO ON DO.Display_Option_Id = SDO.Display_Option_Id
+		WHERE SDO.Sheet_Id = @param_sheet_id
+		AND DO.Display_Option_Desc = @param_option_desc;
+		`
+)
+
+// displayPlotVariables is the number of variables spEM_IEImportDisplayPlots takes per plot.
+const displayPlotVariables = 5
+
+var (
+	queryGetDisplay          = buildQueryGetDisplay("")
+	queryGetDisplayForUpdate = buildQueryGetDisplay("WITH (UPDLOCK, HOLDLOCK)")
+	queryImportDisplay       = buildQueryImportDisplay()
+	queryAddDisplayPlot      = buildQueryAddDisplayPlot()
+)
+
+func buildQueryGetDisplay(hint string) string {
+	columns := []string{"S.Sheet_Id", "S.Sheet_Desc", "S.Master_Unit"}
+	for _, f := range displayFields {
+		columns = append(columns, f.Column)
+	}
+	from := strings.Replace(queryGetDisplayFrom, "dbo.Sheets AS S", "dbo.Sheets AS S "+hint, 1)
+	return "SELECT " + strings.Join(columns, ",\n\t\t\t") + from + "WHERE S.Sheet_Id = @param_sheet_id;"
+}
+
+func buildQueryImportDisplay() string {
+	var b strings.Builder
+	b.WriteString(`
+		SET XACT_ABORT ON;
+
+		DECLARE @pu_desc			NVARCHAR(100);
+
+		IF @param_pu_id IS NOT NULL
+		BEGIN
+			SET @pu_desc = (SELECT PUB.PU_Desc FROM dbo.Prod_Units_Base AS PUB WHERE PUB.PU_Id = @param_pu_id);
+
+			IF @pu_desc IS NULL
+				THROW 50000, 'unit not found', 1;
+		END
+
+		EXEC	@return_value = [dbo].[spEM_IEImportDisplays]
+				@Sheet_Desc = @param_sheet_desc,
+				@PU_Desc = @pu_desc,
+`)
+	for _, f := range displayFields {
+		fmt.Fprintf(&b, "\t\t\t\t@%[1]s = @p_%[1]s,\n", f.Param)
+	}
+	b.WriteString(`				@User_Id = @param_user_id;
+
+		SET @out_Sheet_Id = (SELECT S.Sheet_Id FROM dbo.Sheets AS S WHERE S.Sheet_Desc = @param_sheet_desc);
+`)
+	return b.String()
+}
+
+// buildQueryAddDisplayPlot resolves each plotted variable to the line, unit and variable
+// descriptions spEM_IEImportDisplayPlots expects.
+func buildQueryAddDisplayPlot() string {
+	var b strings.Builder
+	b.WriteString(`
+		SET XACT_ABORT ON;
+
+		DECLARE @sheet_desc NVARCHAR(100);
+`)
+	for i := 1; i <= displayPlotVariables; i++ {
+		fmt.Fprintf(&b, "\t\tDECLARE @pl_desc%[1]d NVARCHAR(100), @pu_desc%[1]d NVARCHAR(100), @var_desc%[1]d NVARCHAR(100);\n", i)
+	}
+	b.WriteString(`
+		SET @sheet_desc = (SELECT S.Sheet_Desc FROM dbo.Sheets AS S WHERE S.Sheet_Id = @param_sheet_id);
+
+		IF @sheet_desc IS NULL
+			THROW 50000, 'display not found', 1;
+`)
+	for i := 1; i <= displayPlotVariables; i++ {
+		fmt.Fprintf(&b, `
+		IF @param_var_id%[1]d IS NOT NULL
+		BEGIN
+			SELECT	@pl_desc%[1]d = PLB.PL_Desc, @pu_desc%[1]d = PUB.PU_Desc, @var_desc%[1]d = VB.Var_Desc
+			FROM dbo.Variables_Base AS VB
+			JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+			JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+			WHERE VB.Var_Id = @param_var_id%[1]d;
+
+			IF @var_desc%[1]d IS NULL
+				THROW 50000, 'plot variable not found', 1;
+		END
+`, i)
+	}
+	b.WriteString(`
+		EXEC	@return_value = [dbo].[spEM_IEImportDisplayPlots]
+				@Sheet_Desc = @sheet_desc,
+				@SPC_Trend_Type_Desc = @param_trend_type,
+				@Plot_Order = @param_plot_order,
+`)
+	for i := 1; i <= displayPlotVariables; i++ {
+		fmt.Fprintf(&b, "\t\t\t\t@PL_Desc%[1]d = @pl_desc%[1]d,\n\t\t\t\t@PU_Desc%[1]d = @pu_desc%[1]d,\n\t\t\t\t@Var_Desc%[1]d = @var_desc%[1]d,\n", i)
+	}
+	b.WriteString("\t\t\t\t@User_Id = @param_user_id;\n")
+	return b.String()
+}
+
+func resourceDisplay() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"display_id": {
+			Type:     schema.TypeInt,
+			Computed: true, // Not settable by user
+		},
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "Sheet_Desc.",
+			ValidateFunc: validation.StringLenBetween(1, 50),
+		},
+		"unit_id": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Master unit of the display.",
+		},
+		"variable": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Columns (or rows) of the display, in order. A block with only a title is a title row. The list owns every variable row of the sheet; rows added outside Terraform are removed.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"variable_id": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"title": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.StringLenBetween(0, 100),
+					},
+					"activity_order": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"activity_alias": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.StringLenBetween(0, 100),
+					},
+					"target_duration": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"execution_start_duration": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"autocomplete_duration": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"external_url": {
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validation.StringLenBetween(0, 100),
+					},
+				},
+			},
+		},
+		"unit": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Units the display shows events for. PA keeps no order for them.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"unit_id": {
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+				},
+			},
+		},
+		"plot": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Plots of the display, in order. The list owns every plot of the sheet; plots added outside Terraform are removed.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"trend_type": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "SPC_Trend_Type_Desc from dbo.SPC_Trend_Types.",
+					},
+					"variable_ids": {
+						Type:     schema.TypeList,
+						Required: true,
+						MinItems: 1,
+						MaxItems: displayPlotVariables,
+						Elem:     &schema.Schema{Type: schema.TypeInt},
+					},
+				},
+			},
+		},
+		"path_codes": {
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: "Path_Code of each execution path on the display.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+		"options": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Description: "Display option values keyed by Display_Option_Desc. Options left out keep the sheet type's default.",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+	for _, f := range displayFields {
+		s[f.Attribute] = f.Schema
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceDisplayCreate,
+		ReadContext:   resourceDisplayRead,
+		UpdateContext: resourceDisplayUpdate,
+		DeleteContext: resourceDisplayDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDisplayImport,
+		},
+		CustomizeDiff: resourceDisplayCustomizeDiff,
+		Schema:        s,
+	}
+}
+
+func resourceDisplayCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for i, v := range d.Get("variable").([]interface{}) {
+		if !d.NewValueKnown(fmt.Sprintf("variable.%d", i)) {
+			continue
+		}
+		block, _ := v.(map[string]interface{})
+		if block == nil || (block["variable_id"].(int) == 0 && block["title"].(string) == "") {
+			return fmt.Errorf("variable %d needs a variable_id or a title", i+1)
+		}
+	}
+	return nil
+}
+
+// readDisplay returns the display's own attributes keyed by attribute name.
+func readDisplay(row interface{ Scan(...interface{}) error }) (map[string]interface{}, error) {
+	var sheetID int64
+	var name sql.NullString
+	var masterUnit sql.NullInt64
+	dest := []interface{}{&sheetID, &name, &masterUnit}
+	for _, f := range displayFields {
+		switch f.Schema.Type {
+		case schema.TypeBool:
+			dest = append(dest, new(sql.NullBool))
+		case schema.TypeInt:
+			dest = append(dest, new(sql.NullInt64))
+		default:
+			dest = append(dest, new(sql.NullString))
+		}
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{
+		"display_id": sheetID,
+		"name":       nullableStringToString(name),
+		"unit_id":    nullableIdToInt64(masterUnit),
+	}
+	for i, f := range displayFields {
+		switch v := dest[i+3].(type) {
+		case *sql.NullBool:
+			values[f.Attribute] = v.Valid && v.Bool
+		case *sql.NullInt64:
+			values[f.Attribute] = v.Int64
+		case *sql.NullString:
+			values[f.Attribute] = nullableStringToString(*v)
+		}
+	}
+	return values, nil
+}
+
+func loadDisplayVariables(rows *sql.Rows, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []interface{}{}
+	for rows.Next() {
+		var varID, activityOrder, targetDuration, startDuration, autocompleteDuration sql.NullInt64
+		var title, activityAlias, externalURL sql.NullString
+		if err := rows.Scan(&varID, &title, &activityOrder, &activityAlias, &targetDuration,
+			&startDuration, &autocompleteDuration, &externalURL); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"variable_id":              int(nullableIdToInt64(varID)),
+			"title":                    nullableStringToString(title),
+			"activity_order":           int(activityOrder.Int64),
+			"activity_alias":           nullableStringToString(activityAlias),
+			"target_duration":          int(targetDuration.Int64),
+			"execution_start_duration": int(startDuration.Int64),
+			"autocomplete_duration":    int(autocompleteDuration.Int64),
+			"external_url":             nullableStringToString(externalURL),
+		})
+	}
+	return blocks, rows.Err()
+}
+
+func loadDisplayPlots(rows *sql.Rows, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := []interface{}{}
+	for rows.Next() {
+		var trendType sql.NullString
+		varIDs := make([]sql.NullInt64, displayPlotVariables)
+		if err := rows.Scan(&trendType, &varIDs[0], &varIDs[1], &varIDs[2], &varIDs[3], &varIDs[4]); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"trend_type":   nullableStringToString(trendType),
+			"variable_ids": scanFaultReasons(varIDs),
+		})
+	}
+	return blocks, rows.Err()
+}
+
+// loadDisplayStrings reads every row as strings; path codes have one column, options two.
+func loadDisplayStrings(rows *sql.Rows, err error) ([][]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var result [][]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(columns))
+		for i, v := range values {
+			row[i] = nullableStringToString(v)
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// optionalIntString renders 0 as NULL and anything else as the string the import procedures take.
+func optionalIntString(v int) sql.NullString {
+	if v == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: int64ToString(int64(v)), Valid: true}
+}
+
+func execImportDisplay(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) (int64, error) {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	var outSheetID sql.NullInt64
+	args := []interface{}{
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_sheet_desc", d.Get("name").(string)),
+		sql.Named("param_pu_id", idToNullInt64(int64(d.Get("unit_id").(int)))),
+		sql.Named("param_user_id", userId),
+		sql.Named("out_Sheet_Id", sql.Out{Dest: &outSheetID}),
+	}
+	for _, f := range displayFields {
+		args = append(args, sql.Named("p_"+f.Param, f.procValue(d)))
+	}
+
+	if _, err := tx.ExecContext(ctx, queryImportDisplay, args...); err != nil {
+		return 0, err
+	}
+	if returnValue.Int64 != 0 || !outSheetID.Valid {
+		return 0, fmt.Errorf(
+			"stored procedure returned failure status: return_value=%v, outSheetID.Valid=%v",
+			returnValue.Int64, outSheetID.Valid)
+	}
+	return outSheetID.Int64, nil
+}
+
+func execDisplayProc(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) error {
+	var returnValue sql.NullInt64
+	args = append(args, sql.Named("return_value", sql.Out{Dest: &returnValue}), sql.Named("param_user_id", 1))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+// displayVariableArgs renders the variable blocks as spEM_IEImportDisplayVariables rows.
+// Var_Order follows the block order, starting at 1.
+func displayVariableArgs(sheetID int64, blocks []interface{}) [][]interface{} {
+	var rows [][]interface{}
+	for i, v := range blocks {
+		block := v.(map[string]interface{})
+		rows = append(rows, []interface{}{
+			sql.Named("param_sheet_id", sheetID),
+			sql.Named("param_var_id", idToNullInt64(int64(block["variable_id"].(int)))),
+			sql.Named("param_title", stringToNullString(block["title"].(string))),
+			sql.Named("param_var_order", int64ToString(int64(i+1))),
+			sql.Named("param_activity_order", optionalIntString(block["activity_order"].(int))),
+			sql.Named("param_activity_alias", stringToNullString(block["activity_alias"].(string))),
+			sql.Named("param_target_duration", optionalIntString(block["target_duration"].(int))),
+			sql.Named("param_execution_start_duration", optionalIntString(block["execution_start_duration"].(int))),
+			sql.Named("param_autocomplete_duration", optionalIntString(block["autocomplete_duration"].(int))),
+			sql.Named("param_external_url", stringToNullString(block["external_url"].(string))),
+		})
+	}
+	return rows
+}
+
+// displayPlotArgs renders the plot blocks as plot rows. Plot_Order follows the block order,
+// and variable IDs past the block's list are NULL up to displayPlotVariables.
+func displayPlotArgs(sheetID int64, blocks []interface{}) [][]interface{} {
+	var rows [][]interface{}
+	for i, v := range blocks {
+		block := v.(map[string]interface{})
+		args := []interface{}{
+			sql.Named("param_sheet_id", sheetID),
+			sql.Named("param_trend_type", block["trend_type"].(string)),
+			sql.Named("param_plot_order", i+1),
+		}
+		varIDs := block["variable_ids"].([]interface{})
+		for j := 0; j < displayPlotVariables; j++ {
+			value := sql.NullInt64{}
+			if j < len(varIDs) {
+				value = idToNullInt64(int64(varIDs[j].(int)))
+			}
+			args = append(args, sql.Named(fmt.Sprintf("param_var_id%d", j+1), value))
+		}
+		rows = append(rows, args)
+	}
+	return rows
+}
+
+// firstChangedDisplayBlock returns the index of the first block that differs between old and
+// new, or the length of the shorter list when one is a prefix of the other.
+func firstChangedDisplayBlock(old, new []interface{}) int {
+	i := 0
+	for i < len(old) && i < len(new) && reflect.DeepEqual(old[i], new[i]) {
+		i++
+	}
+	return i
+}
+
+// syncDisplayChildren writes the variables, units, plots, paths and options that changed. PA
+// orders variables and plots by Var_Order and Plot_Order only, so the rows from the first
+// changed block on are cleared and added again in configuration order; that is also how a
+// reordered column moves. Rows before it are left alone.
+func syncDisplayChildren(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, sheetID int64, create bool) error {
+	if create || d.HasChange("variable") {
+		o, n := d.GetChange("variable")
+		from := firstChangedDisplayBlock(o.([]interface{}), n.([]interface{}))
+		if create {
+			from = 0
+		}
+		if _, err := tx.ExecContext(ctx, queryClearDisplayVariables,
+			sql.Named("param_sheet_id", sheetID),
+			sql.Named("param_from_order", from+1),
+		); err != nil {
+			return err
+		}
+		for i, args := range displayVariableArgs(sheetID, n.([]interface{}))[from:] {
+			if err := execDisplayProc(ctx, tx, queryAddDisplayVariable, args...); err != nil {
+				return fmt.Errorf("failed to add display variable %d: %w", from+i+1, err)
+			}
+		}
+	}
+
+	if create || d.HasChange("unit") {
+		o, n := d.GetChange("unit")
+		removed := o.(*schema.Set).Difference(n.(*schema.Set)).List()
+		added := n.(*schema.Set).Difference(o.(*schema.Set)).List()
+		for _, v := range removed {
+			unitID := int64(v.(map[string]interface{})["unit_id"].(int))
+			if _, err := tx.ExecContext(ctx, queryRemoveDisplayUnit,
+				sql.Named("param_sheet_id", sheetID),
+				sql.Named("param_pu_id", unitID),
+			); err != nil {
+				return fmt.Errorf("failed to remove display unit %d: %w", unitID, err)
+			}
+		}
+		for _, v := range added {
+			unitID := int64(v.(map[string]interface{})["unit_id"].(int))
+			if err := execDisplayProc(ctx, tx, queryAddDisplayUnit,
+				sql.Named("param_sheet_id", sheetID),
+				sql.Named("param_pu_id", unitID),
+			); err != nil {
+				return fmt.Errorf("failed to add display unit %d: %w", unitID, err)
+			}
+		}
+	}
+
+	if create || d.HasChange("plot") {
+		o, n := d.GetChange("plot")
+		from := firstChangedDisplayBlock(o.([]interface{}), n.([]interface{}))
+		if create {
+			from = 0
+		}
+		if _, err := tx.ExecContext(ctx, queryClearDisplayPlots,
+			sql.Named("param_sheet_id", sheetID),
+			sql.Named("param_from_order", from+1),
+		); err != nil {
+			return err
+		}
+		for i, args := range displayPlotArgs(sheetID, n.([]interface{}))[from:] {
+			if err := execDisplayProc(ctx, tx, queryAddDisplayPlot, args...); err != nil {
+				return fmt.Errorf("failed to add display plot %d: %w", from+i+1, err)
+			}
+		}
+	}
+
+	if create || d.HasChange("path_codes") {
+		o, n := d.GetChange("path_codes")
+		for _, v := range o.(*schema.Set).Difference(n.(*schema.Set)).List() {
+			if _, err := tx.ExecContext(ctx, queryRemoveDisplayPath,
+				sql.Named("param_sheet_id", sheetID),
+				sql.Named("param_path_code", v.(string)),
+			); err != nil {
+				return fmt.Errorf("failed to remove display path %q: %w", v, err)
+			}
+		}
+		for _, v := range n.(*schema.Set).Difference(o.(*schema.Set)).List() {
+			if err := execDisplayProc(ctx, tx, queryAddDisplayPath,
+				sql.Named("param_sheet_id", sheetID),
+				sql.Named("param_path_code", v.(string)),
+			); err != nil {
+				return fmt.Errorf("failed to add display path %q: %w", v, err)
+			}
+		}
+	}
+
+	if create || d.HasChange("options") {
+		o, n := d.GetChange("options")
+		oldOptions, newOptions := o.(map[string]interface{}), n.(map[string]interface{})
+		for option := range oldOptions {
+			if _, ok := newOptions[option]; ok {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, queryResetDisplayOption,
+				sql.Named("param_sheet_id", sheetID),
+				sql.Named("param_option_desc", option),
+			); err != nil {
+				return fmt.Errorf("failed to reset display option %q: %w", option, err)
+			}
+		}
+		for option, value := range newOptions {
+			if old, ok := oldOptions[option]; ok && old == value && !create {
+				continue
+			}
+			if err := execDisplayProc(ctx, tx, querySetDisplayOption,
+				sql.Named("param_sheet_id", sheetID),
+				sql.Named("param_option_desc", option),
+				sql.Named("param_option_value", value.(string)),
+			); err != nil {
+				return fmt.Errorf("failed to set display option %q: %w", option, err)
+			}
+		}
+	}
+	return nil
+}
+
+func resourceDisplayCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	var sheetID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetDisplayIdByName, sql.Named("param_sheet_desc", name)).Scan(&sheetID)
+		if err == nil {
+			return fmt.Errorf("display %q already exists; import it instead", name)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		sheetID, err = execImportDisplay(ctx, tx, d)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		return syncDisplayChildren(ctx, tx, d, sheetID, true)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("display_id", int(sheetID))
+	d.SetId(int64ToString(sheetID))
+	return resourceDisplayRead(ctx, d, m)
+}
+
+func resourceDisplayRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values, err := readDisplay(db.QueryRowContext(ctx, queryGetDisplay, sql.Named("param_sheet_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for key, value := range values {
+		d.Set(key, value)
+	}
+
+	variables, err := loadDisplayVariables(db.QueryContext(ctx, queryLoadDisplayVariables, sql.Named("param_sheet_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("variable", variables); err != nil {
+		return diag.FromErr(err)
+	}
+
+	plots, err := loadDisplayPlots(db.QueryContext(ctx, queryLoadDisplayPlots, sql.Named("param_sheet_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("plot", plots); err != nil {
+		return diag.FromErr(err)
+	}
+
+	unitRows, err := db.QueryContext(ctx, queryLoadDisplayUnits, sql.Named("param_sheet_id", id))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var units []interface{}
+	for unitRows.Next() {
+		var unitID int64
+		if err := unitRows.Scan(&unitID); err != nil {
+			unitRows.Close()
+			return diag.FromErr(err)
+		}
+		units = append(units, map[string]interface{}{"unit_id": int(unitID)})
+	}
+	unitRows.Close()
+	if err := unitRows.Err(); err != nil {
+		return diag.FromErr(err)
+	}
+	if err := d.Set("unit", units); err != nil {
+		return diag.FromErr(err)
+	}
+
+	paths, err := loadDisplayStrings(db.QueryContext(ctx, queryLoadDisplayPaths, sql.Named("param_sheet_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var pathCodes []interface{}
+	for _, row := range paths {
+		pathCodes = append(pathCodes, row[0])
+	}
+	if err := d.Set("path_codes", pathCodes); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Only options already in state are read back; the rest are the sheet type's defaults.
+	optionRows, err := loadDisplayStrings(db.QueryContext(ctx, queryLoadDisplayOptions, sql.Named("param_sheet_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	configured := d.Get("options").(map[string]interface{})
+	options := map[string]interface{}{}
+	for _, row := range optionRows {
+		if _, ok := configured[row[0]]; ok {
+			options[row[0]] = row[1]
+		}
+	}
+	if err := d.Set("options", options); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceDisplayUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := readDisplay(tx.QueryRowContext(ctx, queryGetDisplayForUpdate, sql.Named("param_sheet_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("display %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		current["variable"], err = loadDisplayVariables(tx.QueryContext(ctx, queryLoadDisplayVariables, sql.Named("param_sheet_id", id)))
+		if err != nil {
+			return err
+		}
+		current["plot"], err = loadDisplayPlots(tx.QueryContext(ctx, queryLoadDisplayPlots, sql.Named("param_sheet_id", id)))
+		if err != nil {
+			return err
+		}
+		delete(current, "display_id")
+		if err := checkUnchanged(d, "display", current); err != nil {
+			return err
+		}
+
+		// The procedure finds the display by Sheet_Desc, so a new name is written first.
+		if d.HasChange("name") {
+			_, err := tx.ExecContext(ctx, queryRenameDisplay,
+				sql.Named("param_sheet_id", id),
+				sql.Named("param_sheet_desc", d.Get("name").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := execImportDisplay(ctx, tx, d); err != nil {
+			return err
+		}
+		return syncDisplayChildren(ctx, tx, d, id, false)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDisplayRead(ctx, d, m)
+}
+
+func resourceDisplayDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteDisplay, sql.Named("param_sheet_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceDisplayImport accepts the display name.
+func resourceDisplayImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetDisplayIdByName,
+		sql.Named("param_sheet_desc", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("display %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// namedArgValues maps each sql.NamedArg to its value.
+func namedArgValues(args []interface{}) map[string]interface{} {
+	values := map[string]interface{}{}
+	for _, arg := range args {
+		named := arg.(sql.NamedArg)
+		values[named.Name] = named.Value
+	}
+	return values
+}
+
+func displayVariableBlock(variableID int, title string) map[string]interface{} {
+	return map[string]interface{}{
+		"variable_id":              variableID,
+		"title":                    title,
+		"activity_order":           0,
+		"activity_alias":           "",
+		"target_duration":          0,
+		"execution_start_duration": 0,
+		"autocomplete_duration":    0,
+		"external_url":             "",
+	}
+}
+
+func TestDisplayVariableArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		blocks    []interface{}
+		wantVars  []sql.NullInt64
+		wantTitle []sql.NullString
+	}{
+		{
+			name:      "configuration order",
+			blocks:    []interface{}{displayVariableBlock(30, ""), displayVariableBlock(10, ""), displayVariableBlock(20, "")},
+			wantVars:  []sql.NullInt64{{Int64: 30, Valid: true}, {Int64: 10, Valid: true}, {Int64: 20, Valid: true}},
+			wantTitle: []sql.NullString{{}, {}, {}},
+		},
+		{
+			name:      "title rows keep their place",
+			blocks:    []interface{}{displayVariableBlock(0, "Weights"), displayVariableBlock(10, ""), displayVariableBlock(0, "Temperatures")},
+			wantVars:  []sql.NullInt64{{}, {Int64: 10, Valid: true}, {}},
+			wantTitle: []sql.NullString{{String: "Weights", Valid: true}, {}, {String: "Temperatures", Valid: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := displayVariableArgs(4, tt.blocks)
+			if len(rows) != len(tt.blocks) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.blocks))
+			}
+			for i, args := range rows {
+				values := namedArgValues(args)
+				if got, want := values["param_var_order"], fmt.Sprint(i+1); got != want {
+					t.Errorf("row %d: param_var_order = %v, want %v", i, got, want)
+				}
+				if got := values["param_sheet_id"]; got != int64(4) {
+					t.Errorf("row %d: param_sheet_id = %v, want 4", i, got)
+				}
+				if got := values["param_var_id"]; got != tt.wantVars[i] {
+					t.Errorf("row %d: param_var_id = %v, want %v", i, got, tt.wantVars[i])
+				}
+				if got := values["param_title"]; got != tt.wantTitle[i] {
+					t.Errorf("row %d: param_title = %v, want %v", i, got, tt.wantTitle[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDisplayPlotArgs(t *testing.T) {
+	plot := func(trendType string, varIDs ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"trend_type": trendType, "variable_ids": varIDs}
+	}
+	ids := func(varIDs ...int64) []sql.NullInt64 {
+		values := make([]sql.NullInt64, displayPlotVariables)
+		for i, id := range varIDs {
+			values[i] = idToNullInt64(id)
+		}
+		return values
+	}
+
+	tests := []struct {
+		name       string
+		blocks     []interface{}
+		wantTrends []string
+		wantVarIDs [][]sql.NullInt64
+	}{
+		{
+			name:       "configuration order with padded variables",
+			blocks:     []interface{}{plot("Xbar", 12), plot("Run", 11, 13)},
+			wantTrends: []string{"Xbar", "Run"},
+			wantVarIDs: [][]sql.NullInt64{ids(12), ids(11, 13)},
+		},
+		{
+			name:       "plot without variables",
+			blocks:     []interface{}{plot("Histogram")},
+			wantTrends: []string{"Histogram"},
+			wantVarIDs: [][]sql.NullInt64{ids()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := displayPlotArgs(4, tt.blocks)
+			if len(rows) != len(tt.blocks) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.blocks))
+			}
+			for i, args := range rows {
+				if len(args) != 3+displayPlotVariables {
+					t.Fatalf("row %d: got %d args, want %d", i, len(args), 3+displayPlotVariables)
+				}
+				values := namedArgValues(args)
+				if got := values["param_plot_order"]; got != i+1 {
+					t.Errorf("row %d: param_plot_order = %v, want %d", i, got, i+1)
+				}
+				if got := values["param_trend_type"]; got != tt.wantTrends[i] {
+					t.Errorf("row %d: param_trend_type = %v, want %v", i, got, tt.wantTrends[i])
+				}
+				var varIDs []sql.NullInt64
+				for j := 1; j <= displayPlotVariables; j++ {
+					varIDs = append(varIDs, values[fmt.Sprintf("param_var_id%d", j)].(sql.NullInt64))
+				}
+				if !reflect.DeepEqual(varIDs, tt.wantVarIDs[i]) {
+					t.Errorf("row %d: variable IDs = %v, want %v", i, varIDs, tt.wantVarIDs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFirstChangedDisplayBlock(t *testing.T) {
+	a, b, c := displayVariableBlock(10, ""), displayVariableBlock(20, ""), displayVariableBlock(0, "Weights")
+
+	tests := []struct {
+		name string
+		old  []interface{}
+		new  []interface{}
+		want int
+	}{
+		{name: "new display", new: []interface{}{a, b}, want: 0},
+		{name: "unchanged", old: []interface{}{a, b}, new: []interface{}{a, b}, want: 2},
+		{name: "appended", old: []interface{}{a}, new: []interface{}{a, b}, want: 1},
+		{name: "last removed", old: []interface{}{a, b, c}, new: []interface{}{a, b}, want: 2},
+		{name: "swapped", old: []interface{}{a, b, c}, new: []interface{}{a, c, b}, want: 1},
+		{name: "first changed", old: []interface{}{a, b}, new: []interface{}{c, b}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := firstChangedDisplayBlock(tt.old, tt.new); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
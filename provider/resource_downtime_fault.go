@@ -0,0 +1,369 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type DowntimeFault struct {
+	Fault_Id    int64
+	Unit_Id     int64
+	Value       string
+	Name        string
+	Location_Id int64
+	ReasonIds   []interface{}
+}
+
+const (
+	queryGetDowntimeFault = `
+		SELECT	TEF.TEFault_Id, TEF.PU_Id, TEF.TEFault_Value, TEF.TEFault_Name, TEF.Source_PU_Id,
+				TEF.Reason_Level1, TEF.Reason_Level2, TEF.Reason_Level3, TEF.Reason_Level4
+		FROM dbo.Timed_Event_Fault AS TEF
+		WHERE TEF.TEFault_Id = @param_fault_id;
+		`
+
+	queryGetDowntimeFaultForUpdate = `
+		SELECT	TEF.TEFault_Id, TEF.PU_Id, TEF.TEFault_Value, TEF.TEFault_Name, TEF.Source_PU_Id,
+				TEF.Reason_Level1, TEF.Reason_Level2, TEF.Reason_Level3, TEF.Reason_Level4
+		FROM dbo.Timed_Event_Fault AS TEF WITH (UPDLOCK, HOLDLOCK)
+		WHERE TEF.TEFault_Id = @param_fault_id;
+		`
+
+	queryGetDowntimeFaultIdByValue = `
+		SELECT TEF.TEFault_Id
+		FROM dbo.Timed_Event_Fault AS TEF
+		WHERE TEF.PU_Id = @param_pu_id
+		AND TEF.TEFault_Value = @param_fault_value;
+		`
+
+	queryGetDowntimeFaultIdByPath = `
+		SELECT TEF.TEFault_Id
+		FROM dbo.Timed_Event_Fault AS TEF
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = TEF.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PLB.PL_Desc = @param_pl_desc
+		AND PUB.PU_Desc = @param_pu_desc
+		AND TEF.TEFault_Value = @param_fault_value;
+		`
+
+	// spEM_IEImportTimedEventFault creates or updates the fault with FaultValue on the unit. The
+	// reasons must already be in the unit's downtime reason tree.
+	queryImportDowntimeFault = `
+		SET XACT_ABORT ON;
+
+		DECLARE @pl_desc			NVARCHAR(100),
+				@pu_desc			NVARCHAR(100),
+				@location_desc		NVARCHAR(100),
+				@r1					NVARCHAR(100),
+				@r2					NVARCHAR(100),
+				@r3					NVARCHAR(100),
+				@r4					NVARCHAR(100);
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc
+		FROM dbo.Prod_Units_Base AS PUB
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+
+		IF @pu_desc IS NULL
+			THROW 50000, 'unit not found', 1;
+
+		IF @param_location_id IS NOT NULL
+		BEGIN
+			SET @location_desc = (
+				SELECT PUB.PU_Desc FROM dbo.Prod_Units_Base AS PUB WHERE PUB.PU_Id = @param_location_id);
+
+			IF @location_desc IS NULL
+				THROW 50000, 'location unit not found', 1;
+		END
+
+		SET @r1 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason1);
+		SET @r2 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason2);
+		SET @r3 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason3);
+		SET @r4 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason4);
+
+		IF (@param_reason1 IS NOT NULL AND @r1 IS NULL)
+			OR (@param_reason2 IS NOT NULL AND @r2 IS NULL)
+			OR (@param_reason3 IS NOT NULL AND @r3 IS NULL)
+			OR (@param_reason4 IS NOT NULL AND @r4 IS NULL)
+			THROW 50000, 'event reason not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportTimedEventFault]
+				@LineDesc = @pl_desc,
+				@UnitDesc = @pu_desc,
+				@FaultValue = @param_fault_value,
+				@FaultDesc = @param_fault_name,
+				@LocationDesc = @location_desc,
+				@RLevel1 = @r1,
+				@RLevel2 = @r2,
+				@RLevel3 = @r3,
+				@RLevel4 = @r4,
+				@AddReasons = '0',
+				@User_Id = @param_user_id;
+		`
+
+	queryRenameDowntimeFault = `
+		UPDATE dbo.Timed_Event_Fault SET
+			TEFault_Value = @param_fault_value
+		WHERE TEFault_Id = @param_fault_id;
+		`
+
+	queryDeleteDowntimeFault = `
+		DELETE FROM dbo.Timed_Event_Fault
+		WHERE TEFault_Id = @param_fault_id;
+		`
+)
+
+// downtimeFaultSchema is shared with the fault blocks of pa_downtime_faults.
+func downtimeFaultSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"value": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "TEFault_Value, the value the PLC reports.",
+			ValidateFunc: validation.StringLenBetween(1, 100),
+		},
+		"name": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "TEFault_Name.",
+			ValidateFunc: validation.StringLenBetween(0, 100),
+		},
+		"location_id": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Unit the downtime is assigned to.",
+		},
+		"reason_ids": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    reasonTreeDepth,
+			Description: "Reason path, from level 1 down.",
+			Elem:        &schema.Schema{Type: schema.TypeInt},
+		},
+	}
+}
+
+func resourceDowntimeFault() *schema.Resource {
+	s := downtimeFaultSchema()
+	s["fault_id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true, // Not settable by user
+	}
+	s["unit_id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Required: true,
+		ForceNew: true,
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceDowntimeFaultCreate,
+		ReadContext:   resourceDowntimeFaultRead,
+		UpdateContext: resourceDowntimeFaultUpdate,
+		DeleteContext: resourceDowntimeFaultDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDowntimeFaultImport,
+		},
+		Schema: s,
+	}
+}
+
+func scanDowntimeFault(row interface{ Scan(...interface{}) error }) (*DowntimeFault, error) {
+	var fault DowntimeFault
+	var value, name sql.NullString
+	var locationID sql.NullInt64
+	levels := make([]sql.NullInt64, reasonTreeDepth)
+	if err := row.Scan(&fault.Fault_Id, &fault.Unit_Id, &value, &name, &locationID,
+		&levels[0], &levels[1], &levels[2], &levels[3]); err != nil {
+		return nil, err
+	}
+	fault.Value = nullableStringToString(value)
+	fault.Name = nullableStringToString(name)
+	fault.Location_Id = nullableIdToInt64(locationID)
+	fault.ReasonIds = scanFaultReasons(levels)
+	return &fault, nil
+}
+
+func execImportDowntimeFault(ctx context.Context, tx *sql.Tx, fault *DowntimeFault) error {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	args := append(faultReasonArgs(fault.ReasonIds),
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_pu_id", fault.Unit_Id),
+		sql.Named("param_fault_value", fault.Value),
+		sql.Named("param_fault_name", stringToNullString(fault.Name)),
+		sql.Named("param_location_id", idToNullInt64(fault.Location_Id)),
+		sql.Named("param_user_id", userId),
+	)
+	if _, err := tx.ExecContext(ctx, queryImportDowntimeFault, args...); err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func downtimeFaultData(d *schema.ResourceData) *DowntimeFault {
+	return &DowntimeFault{
+		Unit_Id:     int64(d.Get("unit_id").(int)),
+		Value:       d.Get("value").(string),
+		Name:        d.Get("name").(string),
+		Location_Id: int64(d.Get("location_id").(int)),
+		ReasonIds:   d.Get("reason_ids").([]interface{}),
+	}
+}
+
+func resourceDowntimeFaultCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	fault := downtimeFaultData(d)
+
+	var faultID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetDowntimeFaultIdByValue,
+			sql.Named("param_pu_id", fault.Unit_Id),
+			sql.Named("param_fault_value", fault.Value),
+		).Scan(&faultID)
+		if err == nil {
+			return fmt.Errorf("unit %d already has a downtime fault %q; import it instead", fault.Unit_Id, fault.Value)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportDowntimeFault(ctx, tx, fault); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetDowntimeFaultIdByValue,
+			sql.Named("param_pu_id", fault.Unit_Id),
+			sql.Named("param_fault_value", fault.Value),
+		).Scan(&faultID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("downtime fault %q was not created", fault.Value)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("fault_id", int(faultID))
+	d.SetId(int64ToString(faultID))
+	return resourceDowntimeFaultRead(ctx, d, m)
+}
+
+func resourceDowntimeFaultRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	fault, err := scanDowntimeFault(db.QueryRowContext(ctx, queryGetDowntimeFault, sql.Named("param_fault_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("fault_id", fault.Fault_Id)
+	d.Set("unit_id", fault.Unit_Id)
+	d.Set("value", fault.Value)
+	d.Set("name", fault.Name)
+	d.Set("location_id", fault.Location_Id)
+	d.Set("reason_ids", fault.ReasonIds)
+	return nil
+}
+
+func resourceDowntimeFaultUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanDowntimeFault(tx.QueryRowContext(ctx, queryGetDowntimeFaultForUpdate, sql.Named("param_fault_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("downtime fault %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "downtime fault", map[string]interface{}{
+			"value":       current.Value,
+			"name":        current.Name,
+			"location_id": current.Location_Id,
+			"reason_ids":  current.ReasonIds,
+		}); err != nil {
+			return err
+		}
+
+		// The procedure finds the fault by value, so a new value is written first.
+		if d.HasChange("value") {
+			_, err := tx.ExecContext(ctx, queryRenameDowntimeFault,
+				sql.Named("param_fault_id", id),
+				sql.Named("param_fault_value", d.Get("value").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return execImportDowntimeFault(ctx, tx, downtimeFaultData(d))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDowntimeFaultRead(ctx, d, m)
+}
+
+func resourceDowntimeFaultDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteDowntimeFault, sql.Named("param_fault_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceDowntimeFaultImport accepts "Line/Unit/FaultValue".
+func resourceDowntimeFaultImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Line", "Unit", "FaultValue")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetDowntimeFaultIdByPath,
+		sql.Named("param_pl_desc", parts[0]),
+		sql.Named("param_pu_desc", parts[1]),
+		sql.Named("param_fault_value", parts[2]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("downtime fault %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryLoadDowntimeFaults = `
+		SELECT	TEF.TEFault_Id, TEF.PU_Id, TEF.TEFault_Value, TEF.TEFault_Name, TEF.Source_PU_Id,
+				TEF.Reason_Level1, TEF.Reason_Level2, TEF.Reason_Level3, TEF.Reason_Level4
+		FROM dbo.Timed_Event_Fault AS TEF
+		WHERE TEF.PU_Id = @param_pu_id
+		ORDER BY TEF.TEFault_Value;
+		`
+
+	queryLoadDowntimeFaultsForUpdate = `
+		SELECT	TEF.TEFault_Id, TEF.PU_Id, TEF.TEFault_Value, TEF.TEFault_Name, TEF.Source_PU_Id,
+				TEF.Reason_Level1, TEF.Reason_Level2, TEF.Reason_Level3, TEF.Reason_Level4
+		FROM dbo.Timed_Event_Fault AS TEF WITH (UPDLOCK, HOLDLOCK)
+		WHERE TEF.PU_Id = @param_pu_id
+		ORDER BY TEF.TEFault_Value;
+		`
+)
+
+// resourceDowntimeFaults owns the whole downtime fault table of one unit.
+func resourceDowntimeFaults() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDowntimeFaultsCreate,
+		ReadContext:   resourceDowntimeFaultsRead,
+		UpdateContext: resourceDowntimeFaultsUpdate,
+		DeleteContext: resourceDowntimeFaultsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUnitImport,
+		},
+		CustomizeDiff: resourceDowntimeFaultsCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"unit_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"fault": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Every downtime fault of the unit. Faults added outside Terraform are removed.",
+				Elem:        &schema.Resource{Schema: downtimeFaultSchema()},
+			},
+		},
+	}
+}
+
+func resourceDowntimeFaultsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	seen := map[string]bool{}
+	for _, v := range d.Get("fault").(*schema.Set).List() {
+		value := v.(map[string]interface{})["value"].(string)
+		if value != "" && seen[value] {
+			return fmt.Errorf("fault value %q has more than one fault block", value)
+		}
+		seen[value] = true
+	}
+	return nil
+}
+
+func loadDowntimeFaults(rows *sql.Rows, err error) ([]*DowntimeFault, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var faults []*DowntimeFault
+	for rows.Next() {
+		fault, err := scanDowntimeFault(rows)
+		if err != nil {
+			return nil, err
+		}
+		faults = append(faults, fault)
+	}
+	return faults, rows.Err()
+}
+
+// downtimeFaultFromBlock reads a fault block of pa_downtime_faults.
+func downtimeFaultFromBlock(unitID int64, block map[string]interface{}) *DowntimeFault {
+	return &DowntimeFault{
+		Unit_Id:     unitID,
+		Value:       block["value"].(string),
+		Name:        block["name"].(string),
+		Location_Id: int64(block["location_id"].(int)),
+		ReasonIds:   block["reason_ids"].([]interface{}),
+	}
+}
+
+// sameDowntimeFault compares everything but the ID.
+func sameDowntimeFault(a, b *DowntimeFault) bool {
+	return a.Name == b.Name &&
+		a.Location_Id == b.Location_Id &&
+		fmt.Sprint(a.ReasonIds) == fmt.Sprint(b.ReasonIds)
+}
+
+// planDowntimeFaults matches current against wanted by value. Faults only in current are
+// removed; faults that are new or differ are imported again. Both come back in value order.
+func planDowntimeFaults(current, wanted []*DowntimeFault) (remove, imports []*DowntimeFault) {
+	byValue := map[string]*DowntimeFault{}
+	for _, fault := range current {
+		byValue[fault.Value] = fault
+	}
+	kept := map[string]bool{}
+	for _, fault := range wanted {
+		kept[fault.Value] = true
+		if existing, ok := byValue[fault.Value]; ok && sameDowntimeFault(existing, fault) {
+			continue
+		}
+		imports = append(imports, fault)
+	}
+	for _, fault := range current {
+		if !kept[fault.Value] {
+			remove = append(remove, fault)
+		}
+	}
+	sort.SliceStable(remove, func(i, j int) bool { return remove[i].Value < remove[j].Value })
+	sort.SliceStable(imports, func(i, j int) bool { return imports[i].Value < imports[j].Value })
+	return remove, imports
+}
+
+// syncDowntimeFaults makes the unit's faults match desired exactly. Faults are matched by value;
+// unchanged ones are left alone and changed ones are imported again.
+func syncDowntimeFaults(ctx context.Context, m interface{}, unitID int64, desired []interface{}) error {
+	return withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := loadDowntimeFaults(tx.QueryContext(ctx, queryLoadDowntimeFaultsForUpdate, sql.Named("param_pu_id", unitID)))
+		if err != nil {
+			return err
+		}
+
+		var wanted []*DowntimeFault
+		for _, v := range desired {
+			wanted = append(wanted, downtimeFaultFromBlock(unitID, v.(map[string]interface{})))
+		}
+
+		remove, imports := planDowntimeFaults(current, wanted)
+		for _, fault := range remove {
+			if _, err := tx.ExecContext(ctx, queryDeleteDowntimeFault, sql.Named("param_fault_id", fault.Fault_Id)); err != nil {
+				return fmt.Errorf("failed to remove downtime fault %q: %w", fault.Value, err)
+			}
+		}
+		for _, fault := range imports {
+			if err := execImportDowntimeFault(ctx, tx, fault); err != nil {
+				return fmt.Errorf("failed to import downtime fault %q: %w", fault.Value, err)
+			}
+		}
+		return nil
+	})
+}
+
+func resourceDowntimeFaultsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unitID := int64(d.Get("unit_id").(int))
+
+	if err := syncDowntimeFaults(ctx, m, unitID, d.Get("fault").(*schema.Set).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(int64ToString(unitID))
+	return resourceDowntimeFaultsRead(ctx, d, m)
+}
+
+func resourceDowntimeFaultsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var unitID int64
+	err = db.QueryRowContext(ctx, queryGetUnitExists, sql.Named("param_pu_id", id)).Scan(&unitID)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	faults, err := loadDowntimeFaults(db.QueryContext(ctx, queryLoadDowntimeFaults, sql.Named("param_pu_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	blocks := make([]interface{}, 0, len(faults))
+	for _, fault := range faults {
+		blocks = append(blocks, map[string]interface{}{
+			"value":       fault.Value,
+			"name":        fault.Name,
+			"location_id": int(fault.Location_Id),
+			"reason_ids":  fault.ReasonIds,
+		})
+	}
+
+	d.Set("unit_id", unitID)
+	if err := d.Set("fault", blocks); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceDowntimeFaultsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := syncDowntimeFaults(ctx, m, id, d.Get("fault").(*schema.Set).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceDowntimeFaultsRead(ctx, d, m)
+}
+
+func resourceDowntimeFaultsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := syncDowntimeFaults(ctx, m, id, nil); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
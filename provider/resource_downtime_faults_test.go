@@ -0,0 +1,89 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func faultValues(faults []*DowntimeFault) []string {
+	values := []string{}
+	for _, fault := range faults {
+		values = append(values, fault.Value)
+	}
+	return values
+}
+
+func TestPlanDowntimeFaults(t *testing.T) {
+	fault := func(id int64, value, name string, location int64, reasons ...interface{}) *DowntimeFault {
+		return &DowntimeFault{Fault_Id: id, Unit_Id: 5, Value: value, Name: name, Location_Id: location, ReasonIds: reasons}
+	}
+
+	tests := []struct {
+		name        string
+		current     []*DowntimeFault
+		wanted      []*DowntimeFault
+		wantRemove  []string
+		wantImports []string
+	}{
+		{
+			name:        "new unit",
+			wanted:      []*DowntimeFault{fault(0, "17", "Infeed jam", 0, 1, 2), fault(0, "12", "Door open", 0)},
+			wantRemove:  []string{},
+			wantImports: []string{"12", "17"},
+		},
+		{
+			name:        "unchanged faults are left alone",
+			current:     []*DowntimeFault{fault(40, "17", "Infeed jam", 6, 1, 2)},
+			wanted:      []*DowntimeFault{fault(0, "17", "Infeed jam", 6, 1, 2)},
+			wantRemove:  []string{},
+			wantImports: []string{},
+		},
+		{
+			name:        "renamed fault is imported again",
+			current:     []*DowntimeFault{fault(40, "17", "Infeed jam", 0)},
+			wanted:      []*DowntimeFault{fault(0, "17", "Infeed jam left", 0)},
+			wantRemove:  []string{},
+			wantImports: []string{"17"},
+		},
+		{
+			name:        "changed location is imported again",
+			current:     []*DowntimeFault{fault(40, "17", "Infeed jam", 6)},
+			wanted:      []*DowntimeFault{fault(0, "17", "Infeed jam", 7)},
+			wantRemove:  []string{},
+			wantImports: []string{"17"},
+		},
+		{
+			name:        "changed reasons are imported again",
+			current:     []*DowntimeFault{fault(40, "17", "Infeed jam", 0, 1, 2)},
+			wanted:      []*DowntimeFault{fault(0, "17", "Infeed jam", 0, 1, 3)},
+			wantRemove:  []string{},
+			wantImports: []string{"17"},
+		},
+		{
+			name:        "faults not configured are removed",
+			current:     []*DowntimeFault{fault(41, "9", "Low air", 0), fault(40, "17", "Infeed jam", 0), fault(42, "3", "E-stop", 0)},
+			wanted:      []*DowntimeFault{fault(0, "17", "Infeed jam", 0)},
+			wantRemove:  []string{"3", "9"},
+			wantImports: []string{},
+		},
+		{
+			name:        "value is the match key, not the name",
+			current:     []*DowntimeFault{fault(40, "17", "Infeed jam", 0)},
+			wanted:      []*DowntimeFault{fault(0, "18", "Infeed jam", 0)},
+			wantRemove:  []string{"17"},
+			wantImports: []string{"18"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remove, imports := planDowntimeFaults(tt.current, tt.wanted)
+			if got := faultValues(remove); !reflect.DeepEqual(got, tt.wantRemove) {
+				t.Errorf("remove = %q, want %q", got, tt.wantRemove)
+			}
+			if got := faultValues(imports); !reflect.DeepEqual(got, tt.wantImports) {
+				t.Errorf("imports = %q, want %q", got, tt.wantImports)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetEngineeringUnit = `
+		SELECT EU.Eng_Unit_Code, EU.Eng_Unit_Desc
+		FROM dbo.Engineering_Unit AS EU
+		WHERE EU.Eng_Unit_Id = @param_eu_id;
+		`
+
+	queryGetEngineeringUnitForUpdate = `
+		SELECT EU.Eng_Unit_Code, EU.Eng_Unit_Desc
+		FROM dbo.Engineering_Unit AS EU WITH (UPDLOCK, HOLDLOCK)
+		WHERE EU.Eng_Unit_Id = @param_eu_id;
+		`
+
+	// Codes compare under the database collation, so "KG" finds an existing "kg".
+	queryGetEngineeringUnitIdByCode = `
+		SELECT EU.Eng_Unit_Id
+		FROM dbo.Engineering_Unit AS EU
+		WHERE EU.Eng_Unit_Code = @param_eu_code;
+		`
+
+	// spEM_IEImportEngineeringUnit creates or updates the unit with EngCode.
+	queryImportEngineeringUnit = `
+		EXEC	@return_value = [dbo].[spEM_IEImportEngineeringUnit]
+				@EngDesc = @param_eu_desc,
+				@EngCode = @param_eu_code,
+				@User_Id = @param_user_id;
+		`
+
+	queryRenameEngineeringUnit = `
+		UPDATE dbo.Engineering_Unit SET
+			Eng_Unit_Code = @param_eu_code
+		WHERE Eng_Unit_Id = @param_eu_id;
+		`
+
+	queryDeleteEngineeringUnit = `
+		SET XACT_ABORT ON;
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Engineering_Unit_Conversion AS EUC
+			WHERE EUC.From_Eng_Unit_Id = @param_eu_id OR EUC.To_Eng_Unit_Id = @param_eu_id)
+			THROW 50000, 'engineering unit is still used by a conversion', 1;
+
+		DELETE FROM dbo.Engineering_Unit WHERE Eng_Unit_Id = @param_eu_id;
+		`
+)
+
+func resourceEngineeringUnit() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceEngineeringUnitCreate,
+		ReadContext:   resourceEngineeringUnitRead,
+		UpdateContext: resourceEngineeringUnitUpdate,
+		DeleteContext: resourceEngineeringUnitDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceEngineeringUnitImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"engineering_unit_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"code": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Eng_Unit_Code, the text variables show as eng_units.",
+				ValidateFunc: validation.StringLenBetween(1, 15),
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Eng_Unit_Desc.",
+				ValidateFunc: validation.StringLenBetween(1, 50),
+			},
+		},
+	}
+}
+
+func execImportEngineeringUnit(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) error {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	_, err := tx.ExecContext(ctx, queryImportEngineeringUnit,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_eu_code", d.Get("code").(string)),
+		sql.Named("param_eu_desc", d.Get("description").(string)),
+		sql.Named("param_user_id", userId),
+	)
+	if err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceEngineeringUnitCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	code := d.Get("code").(string)
+
+	var euID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetEngineeringUnitIdByCode, sql.Named("param_eu_code", code)).Scan(&euID)
+		if err == nil {
+			return fmt.Errorf("engineering unit %q already exists; import it instead", code)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportEngineeringUnit(ctx, tx, d); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetEngineeringUnitIdByCode, sql.Named("param_eu_code", code)).Scan(&euID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("engineering unit %q was not created", code)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("engineering_unit_id", int(euID))
+	d.SetId(int64ToString(euID))
+	return resourceEngineeringUnitRead(ctx, d, m)
+}
+
+func resourceEngineeringUnitRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var code, description sql.NullString
+	err = db.QueryRowContext(ctx, queryGetEngineeringUnit, sql.Named("param_eu_id", id)).Scan(&code, &description)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("engineering_unit_id", id)
+	d.Set("code", nullableStringToString(code))
+	d.Set("description", nullableStringToString(description))
+	return nil
+}
+
+func resourceEngineeringUnitUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var code, description sql.NullString
+		err := tx.QueryRowContext(ctx, queryGetEngineeringUnitForUpdate, sql.Named("param_eu_id", id)).Scan(&code, &description)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("engineering unit %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "engineering unit", map[string]interface{}{
+			"code":        nullableStringToString(code),
+			"description": nullableStringToString(description),
+		}); err != nil {
+			return err
+		}
+
+		// The procedure finds the unit by code, so a new code is written first.
+		if d.HasChange("code") {
+			_, err := tx.ExecContext(ctx, queryRenameEngineeringUnit,
+				sql.Named("param_eu_id", id),
+				sql.Named("param_eu_code", d.Get("code").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return execImportEngineeringUnit(ctx, tx, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceEngineeringUnitRead(ctx, d, m)
+}
+
+func resourceEngineeringUnitDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteEngineeringUnit, sql.Named("param_eu_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceEngineeringUnitImport accepts the unit code.
+func resourceEngineeringUnitImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetEngineeringUnitIdByCode,
+		sql.Named("param_eu_code", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("engineering unit %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type EngineeringUnitConversion struct {
+	Conversion_Id  int64
+	Description    string
+	From_Unit_Id   int64
+	To_Unit_Id     int64
+	Slope          float64
+	Intercept      float64
+	Custom_Formula string
+}
+
+const (
+	queryGetEngineeringUnitConversion = `
+		SELECT	EUC.Eng_Unit_Conv_Id, EUC.Conversion_Desc, EUC.From_Eng_Unit_Id, EUC.To_Eng_Unit_Id,
+				EUC.Slope, EUC.Intercept, EUC.Custom_Conversion
+		FROM dbo.Engineering_Unit_Conversion AS EUC
+		WHERE EUC.Eng_Unit_Conv_Id = @param_conv_id;
+		`
+
+	queryGetEngineeringUnitConversionForUpdate = `
+		SELECT	EUC.Eng_Unit_Conv_Id, EUC.Conversion_Desc, EUC.From_Eng_Unit_Id, EUC.To_Eng_Unit_Id,
+				EUC.Slope, EUC.Intercept, EUC.Custom_Conversion
+		FROM dbo.Engineering_Unit_Conversion AS EUC WITH (UPDLOCK, HOLDLOCK)
+		WHERE EUC.Eng_Unit_Conv_Id = @param_conv_id;
+		`
+
+	queryGetEngineeringUnitConversionIdByDesc = `
+		SELECT EUC.Eng_Unit_Conv_Id
+		FROM dbo.Engineering_Unit_Conversion AS EUC
+		WHERE EUC.Conversion_Desc = @param_conv_desc;
+		`
+
+	// spEM_IEImportEngineeringUnitConversion creates or updates the conversion with ConvDesc.
+	// Either Slope and Intercept or CustSQL is passed; the other is NULL.
+	queryImportEngineeringUnitConversion = `
+		SET XACT_ABORT ON;
+
+		DECLARE @from_code			NVARCHAR(255),
+				@to_code			NVARCHAR(255);
+
+		SET @from_code = (
+			SELECT EU.Eng_Unit_Code FROM dbo.Engineering_Unit AS EU WHERE EU.Eng_Unit_Id = @param_from_eu_id);
+
+		IF @from_code IS NULL
+			THROW 50000, 'from engineering unit not found', 1;
+
+		SET @to_code = (
+			SELECT EU.Eng_Unit_Code FROM dbo.Engineering_Unit AS EU WHERE EU.Eng_Unit_Id = @param_to_eu_id);
+
+		IF @to_code IS NULL
+			THROW 50000, 'to engineering unit not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportEngineeringUnitConversion]
+				@ConvDesc = @param_conv_desc,
+				@FromEngDesc = @from_code,
+				@ToEngDesc = @to_code,
+				@Slope = @param_slope,
+				@Intercept = @param_intercept,
+				@CustSQL = @param_cust_sql,
+				@User_Id = @param_user_id;
+		`
+
+	queryRenameEngineeringUnitConversion = `
+		UPDATE dbo.Engineering_Unit_Conversion SET
+			Conversion_Desc = @param_conv_desc
+		WHERE Eng_Unit_Conv_Id = @param_conv_id;
+		`
+
+	queryDeleteEngineeringUnitConversion = `
+		DELETE FROM dbo.Engineering_Unit_Conversion
+		WHERE Eng_Unit_Conv_Id = @param_conv_id;
+		`
+)
+
+func resourceEngineeringUnitConversion() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceEngineeringUnitConversionCreate,
+		ReadContext:   resourceEngineeringUnitConversionRead,
+		UpdateContext: resourceEngineeringUnitConversionUpdate,
+		DeleteContext: resourceEngineeringUnitConversionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceEngineeringUnitConversionImport,
+		},
+		CustomizeDiff: resourceEngineeringUnitConversionCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"conversion_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Conversion_Desc.",
+				ValidateFunc: validateVarchar255(),
+			},
+			"from_unit_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"to_unit_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"slope": {
+				Type:          schema.TypeFloat,
+				Optional:      true,
+				Description:   "to = from * slope + intercept.",
+				ConflictsWith: []string{"custom_formula"},
+			},
+			"intercept": {
+				Type:          schema.TypeFloat,
+				Optional:      true,
+				ConflictsWith: []string{"custom_formula"},
+			},
+			"custom_formula": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Cust_SQL, used instead of slope and intercept.",
+				ValidateFunc: validation.StringLenBetween(0, 255),
+			},
+		},
+	}
+}
+
+// resourceEngineeringUnitConversionCustomizeDiff requires one of the two conversion forms and
+// rejects converting a unit to itself.
+func resourceEngineeringUnitConversionCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("slope") && d.NewValueKnown("custom_formula") &&
+		d.Get("slope").(float64) == 0 && d.Get("custom_formula").(string) == "" {
+		return fmt.Errorf("one of slope or custom_formula must be set")
+	}
+	if d.NewValueKnown("from_unit_id") && d.NewValueKnown("to_unit_id") &&
+		d.Get("from_unit_id").(int) == d.Get("to_unit_id").(int) {
+		return fmt.Errorf("from_unit_id and to_unit_id must differ")
+	}
+	return nil
+}
+
+func scanEngineeringUnitConversion(row interface{ Scan(...interface{}) error }) (*EngineeringUnitConversion, error) {
+	var conv EngineeringUnitConversion
+	var description, customFormula sql.NullString
+	var fromID, toID sql.NullInt64
+	var slope, intercept sql.NullFloat64
+	if err := row.Scan(&conv.Conversion_Id, &description, &fromID, &toID, &slope, &intercept, &customFormula); err != nil {
+		return nil, err
+	}
+	conv.Description = nullableStringToString(description)
+	conv.From_Unit_Id = nullableIdToInt64(fromID)
+	conv.To_Unit_Id = nullableIdToInt64(toID)
+	conv.Slope = slope.Float64
+	conv.Intercept = intercept.Float64
+	conv.Custom_Formula = nullableStringToString(customFormula)
+	return &conv, nil
+}
+
+func execImportEngineeringUnitConversion(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) error {
+	userId := 1
+
+	var slope, intercept sql.NullString
+	customFormula := d.Get("custom_formula").(string)
+	if customFormula == "" {
+		slope = stringToNullString(strconv.FormatFloat(d.Get("slope").(float64), 'g', -1, 64))
+		intercept = stringToNullString(strconv.FormatFloat(d.Get("intercept").(float64), 'g', -1, 64))
+	}
+
+	var returnValue sql.NullInt64
+	_, err := tx.ExecContext(ctx, queryImportEngineeringUnitConversion,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_conv_desc", d.Get("description").(string)),
+		sql.Named("param_from_eu_id", int64(d.Get("from_unit_id").(int))),
+		sql.Named("param_to_eu_id", int64(d.Get("to_unit_id").(int))),
+		sql.Named("param_slope", slope),
+		sql.Named("param_intercept", intercept),
+		sql.Named("param_cust_sql", stringToNullString(customFormula)),
+		sql.Named("param_user_id", userId),
+	)
+	if err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceEngineeringUnitConversionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	description := d.Get("description").(string)
+
+	var convID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetEngineeringUnitConversionIdByDesc, sql.Named("param_conv_desc", description)).Scan(&convID)
+		if err == nil {
+			return fmt.Errorf("engineering unit conversion %q already exists; import it instead", description)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportEngineeringUnitConversion(ctx, tx, d); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetEngineeringUnitConversionIdByDesc, sql.Named("param_conv_desc", description)).Scan(&convID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("engineering unit conversion %q was not created", description)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("conversion_id", int(convID))
+	d.SetId(int64ToString(convID))
+	return resourceEngineeringUnitConversionRead(ctx, d, m)
+}
+
+func resourceEngineeringUnitConversionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	conv, err := scanEngineeringUnitConversion(db.QueryRowContext(ctx, queryGetEngineeringUnitConversion, sql.Named("param_conv_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("conversion_id", conv.Conversion_Id)
+	d.Set("description", conv.Description)
+	d.Set("from_unit_id", conv.From_Unit_Id)
+	d.Set("to_unit_id", conv.To_Unit_Id)
+	d.Set("slope", conv.Slope)
+	d.Set("intercept", conv.Intercept)
+	d.Set("custom_formula", conv.Custom_Formula)
+	return nil
+}
+
+func resourceEngineeringUnitConversionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanEngineeringUnitConversion(tx.QueryRowContext(ctx, queryGetEngineeringUnitConversionForUpdate, sql.Named("param_conv_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("engineering unit conversion %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "engineering unit conversion", map[string]interface{}{
+			"description":    current.Description,
+			"from_unit_id":   current.From_Unit_Id,
+			"to_unit_id":     current.To_Unit_Id,
+			"slope":          current.Slope,
+			"intercept":      current.Intercept,
+			"custom_formula": current.Custom_Formula,
+		}); err != nil {
+			return err
+		}
+
+		// The procedure finds the conversion by description, so a new one is written first.
+		if d.HasChange("description") {
+			_, err := tx.ExecContext(ctx, queryRenameEngineeringUnitConversion,
+				sql.Named("param_conv_id", id),
+				sql.Named("param_conv_desc", d.Get("description").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return execImportEngineeringUnitConversion(ctx, tx, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceEngineeringUnitConversionRead(ctx, d, m)
+}
+
+func resourceEngineeringUnitConversionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteEngineeringUnitConversion, sql.Named("param_conv_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceEngineeringUnitConversionImport accepts the conversion description.
+func resourceEngineeringUnitConversionImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetEngineeringUnitConversionIdByDesc,
+		sql.Named("param_conv_desc", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("engineering unit conversion %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
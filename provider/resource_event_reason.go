@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type EventReason struct {
+	Reason_Id        int64
+	Name             string
+	Code             string
+	CommentRequired  bool
+	SecurityGroup_Id int64
+	ExternalLink     string
+}
+
+const (
+	queryGetEventReason = `
+		SELECT ER.Event_Reason_Id, ER.Event_Reason_Name, ER.Event_Reason_Code, ER.Comment_Required, ER.Group_Id, ER.External_Link
+		FROM dbo.Event_Reasons AS ER
+		WHERE ER.Event_Reason_Id = @param_reason_id;
+		`
+
+	queryGetEventReasonForUpdate = `
+		SELECT ER.Event_Reason_Id, ER.Event_Reason_Name, ER.Event_Reason_Code, ER.Comment_Required, ER.Group_Id, ER.External_Link
+		FROM dbo.Event_Reasons AS ER WITH (UPDLOCK, HOLDLOCK)
+		WHERE ER.Event_Reason_Id = @param_reason_id;
+		`
+
+	queryGetEventReasonIdByName = `
+		SELECT ER.Event_Reason_Id
+		FROM dbo.Event_Reasons AS ER
+		WHERE ER.Event_Reason_Name = @param_reason_name;
+		`
+
+	// spEM_IEImportEventReasons renames Old_Event_Reason_Name to New_Event_Reason_Name when the
+	// old name exists, and creates the new name otherwise.
+	queryImportEventReason = `
+		SET XACT_ABORT ON;
+
+		DECLARE @sg_desc			NVARCHAR(50);
+
+		IF @param_group_id IS NOT NULL
+		BEGIN
+			SET @sg_desc = (
+				SELECT SG.Group_Desc FROM dbo.Security_Groups AS SG WHERE SG.Group_Id = @param_group_id);
+
+			IF @sg_desc IS NULL
+				THROW 50000, 'security group not found', 1;
+		END
+
+		EXEC	@return_value = [dbo].[spEM_IEImportEventReasons]
+				@Old_Event_Reason_Name = @param_old_name,
+				@New_Event_Reason_Name = @param_reason_name,
+				@Comment_Required = @param_comment_required,
+				@Event_Reason_Code = @param_reason_code,
+				@Group_Desc = @sg_desc,
+				@ExternalLink = @param_ext_link,
+				@User_Id = @param_user_id;
+		`
+
+	queryDeleteEventReason = `
+		SET XACT_ABORT ON;
+
+		IF EXISTS (SELECT 1 FROM dbo.Event_Reason_Tree_Data AS ERTD WHERE ERTD.Event_Reason_Id = @param_reason_id)
+			THROW 50000, 'event reason is still used by a reason tree', 1;
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Timed_Event_Fault AS TEF
+			WHERE TEF.Reason_Level1 = @param_reason_id OR TEF.Reason_Level2 = @param_reason_id
+			OR TEF.Reason_Level3 = @param_reason_id OR TEF.Reason_Level4 = @param_reason_id)
+			THROW 50000, 'event reason is still used by a downtime fault', 1;
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Waste_Event_Fault AS WEF
+			WHERE WEF.Reason_Level1 = @param_reason_id OR WEF.Reason_Level2 = @param_reason_id
+			OR WEF.Reason_Level3 = @param_reason_id OR WEF.Reason_Level4 = @param_reason_id)
+			THROW 50000, 'event reason is still used by a waste fault', 1;
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Reason_Shortcuts AS RS
+			WHERE RS.Reason_Level1 = @param_reason_id OR RS.Reason_Level2 = @param_reason_id
+			OR RS.Reason_Level3 = @param_reason_id OR RS.Reason_Level4 = @param_reason_id)
+			THROW 50000, 'event reason is still used by a reason shortcut', 1;
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Alarm_Templates AS AT
+			WHERE AT.Default_Cause1 = @param_reason_id OR AT.Default_Cause2 = @param_reason_id
+			OR AT.Default_Cause3 = @param_reason_id OR AT.Default_Cause4 = @param_reason_id
+			OR AT.Default_Action1 = @param_reason_id OR AT.Default_Action2 = @param_reason_id
+			OR AT.Default_Action3 = @param_reason_id OR AT.Default_Action4 = @param_reason_id)
+			THROW 50000, 'event reason is still a default cause or action of an alarm template', 1;
+
+		DELETE FROM dbo.Event_Reasons WHERE Event_Reason_Id = @param_reason_id;
+		`
+)
+
+func resourceEventReason() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceEventReasonCreate,
+		ReadContext:   resourceEventReasonRead,
+		UpdateContext: resourceEventReasonUpdate,
+		DeleteContext: resourceEventReasonDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceEventReasonImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"reason_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Event_Reason_Name.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"code": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Event_Reason_Code.",
+				ValidateFunc: validation.StringLenBetween(0, 10),
+			},
+			"comment_required": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"security_group_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"external_link": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+		},
+	}
+}
+
+func scanEventReason(row interface{ Scan(...interface{}) error }) (*EventReason, error) {
+	var reason EventReason
+	var name, code, externalLink sql.NullString
+	var commentRequired sql.NullBool
+	var groupID sql.NullInt64
+	if err := row.Scan(&reason.Reason_Id, &name, &code, &commentRequired, &groupID, &externalLink); err != nil {
+		return nil, err
+	}
+	reason.Name = nullableStringToString(name)
+	reason.Code = nullableStringToString(code)
+	reason.CommentRequired = commentRequired.Bool
+	reason.SecurityGroup_Id = nullableIdToInt64(groupID)
+	reason.ExternalLink = nullableStringToString(externalLink)
+	return &reason, nil
+}
+
+func execImportEventReason(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, oldName string) error {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	_, err := tx.ExecContext(ctx, queryImportEventReason,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_old_name", oldName),
+		sql.Named("param_reason_name", d.Get("name").(string)),
+		sql.Named("param_comment_required", int64ToString(boolToInt64(d.Get("comment_required").(bool)))),
+		sql.Named("param_reason_code", stringToNullString(d.Get("code").(string))),
+		sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+		sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+		sql.Named("param_user_id", userId),
+	)
+	if err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceEventReasonCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	var reasonID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetEventReasonIdByName, sql.Named("param_reason_name", name)).Scan(&reasonID)
+		if err == nil {
+			return fmt.Errorf("event reason %q already exists; import it instead", name)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportEventReason(ctx, tx, d, name); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetEventReasonIdByName, sql.Named("param_reason_name", name)).Scan(&reasonID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("event reason %q was not created", name)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("reason_id", int(reasonID))
+	d.SetId(int64ToString(reasonID))
+	return resourceEventReasonRead(ctx, d, m)
+}
+
+func resourceEventReasonRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	reason, err := scanEventReason(db.QueryRowContext(ctx, queryGetEventReason, sql.Named("param_reason_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("reason_id", reason.Reason_Id)
+	d.Set("name", reason.Name)
+	d.Set("code", reason.Code)
+	d.Set("comment_required", reason.CommentRequired)
+	d.Set("security_group_id", reason.SecurityGroup_Id)
+	d.Set("external_link", reason.ExternalLink)
+	return nil
+}
+
+func resourceEventReasonUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanEventReason(tx.QueryRowContext(ctx, queryGetEventReasonForUpdate, sql.Named("param_reason_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("event reason %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "event reason", map[string]interface{}{
+			"name":              current.Name,
+			"code":              current.Code,
+			"comment_required":  current.CommentRequired,
+			"security_group_id": current.SecurityGroup_Id,
+			"external_link":     current.ExternalLink,
+		}); err != nil {
+			return err
+		}
+		return execImportEventReason(ctx, tx, d, current.Name)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceEventReasonRead(ctx, d, m)
+}
+
+func resourceEventReasonDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteEventReason, sql.Named("param_reason_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceEventReasonImport accepts the reason name.
+func resourceEventReasonImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetEventReasonIdByName,
+		sql.Named("param_reason_name", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("event reason %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -22,7 +23,10 @@ type Line struct {
 }
 
 var (
-	cachedLines map[int64]*Line
+	cachedLines   map[int64]*Line
+	loadLinesOnce sync.Once
+	// cachedLinesMu guards cachedLines; Terraform reads and writes lines in parallel.
+	cachedLinesMu sync.RWMutex
 )
 
 const (
@@ -37,6 +41,43 @@ const (
 		ORDER BY PL_Id DESC;
 		`
 
+	queryGetLine = `
+		SELECT	PLB.PL_Id, PLB.PL_Desc, PLB.Extended_Info, PLB.External_Link, PLB.Group_Id, SG.Group_Desc,
+				DB.Dept_Id, DB.Dept_Desc
+		FROM dbo.Prod_Lines_Base AS PLB
+		LEFT JOIN dbo.Departments_Base AS DB ON DB.Dept_Id = PLB.Dept_Id
+		LEFT JOIN dbo.Security_Groups AS SG ON SG.Group_Id = PLB.Group_Id
+		WHERE PLB.PL_Id = @param_pl_id;
+		`
+
+	queryGetLineForUpdate = `
+		SELECT	PLB.PL_Id, PLB.PL_Desc, PLB.Extended_Info, PLB.External_Link, PLB.Group_Id, SG.Group_Desc,
+				DB.Dept_Id, DB.Dept_Desc
+		FROM dbo.Prod_Lines_Base AS PLB WITH (UPDLOCK, HOLDLOCK)
+		LEFT JOIN dbo.Departments_Base AS DB ON DB.Dept_Id = PLB.Dept_Id
+		LEFT JOIN dbo.Security_Groups AS SG ON SG.Group_Id = PLB.Group_Id
+		WHERE PLB.PL_Id = @param_pl_id;
+		`
+
+	queryLoadDeletedLines = `
+		SELECT	PLB.PL_Id, PLB.Extended_Info, PLB.External_Link, PLB.Group_Id, PLB.Dept_Id
+		FROM dbo.Prod_Lines_Base AS PLB
+		WHERE PLB.PL_Id >= 0
+		AND PLB.PL_Desc = '<PL Deleted>'
+		ORDER BY PLB.PL_Id DESC;
+		`
+
+	queryRestoreLine = `
+		UPDATE dbo.Prod_Lines_Base SET
+			PL_Desc = @param_pl_desc,
+			Dept_Id = @param_dept_id,
+			Extended_Info = @param_ext_info,
+			External_Link = @param_ext_link,
+			Group_Id = @param_group_id
+		WHERE PL_Id = @param_pl_id
+		AND PL_Desc = '<PL Deleted>';
+		`
+
 	queryCreateLine = `
 		--DECLARE	@return_value		int,
 		--		@out_PL_Id			int;
@@ -48,6 +89,8 @@ const (
 		--		@param_group_id		NVARCHAR(255)	= NULL,
 		--		@param_user_id		int				= 1;
 		
+		SET XACT_ABORT ON;
+
 		DECLARE @dept_desc			NVARCHAR(255),
 				@sg_desc			NVARCHAR(255);
 		
@@ -84,6 +127,9 @@ const (
 		SELECT PL_Id FROM dbo.Prod_Lines_Base AS PLB
 		WHERE PLB.PL_Desc = @param_pl_desc;
 	`
+
+	// lineDeletedDesc is the description spEM_DropLine leaves behind on a soft-deleted line.
+	lineDeletedDesc = "<PL Deleted>"
 )
 
 func resourceLine() *schema.Resource {
@@ -92,6 +138,7 @@ func resourceLine() *schema.Resource {
 		ReadContext:   resourceLineRead,
 		UpdateContext: resourceLineUpdate,
 		DeleteContext: resourceLineDelete,
+		CustomizeDiff: resourceLineCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -123,6 +170,16 @@ func resourceLine() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
+			"restore_if_deleted": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"deletion_protection": deletionProtectionSchema(),
+			"soft_deleted": {
+				Type:     schema.TypeBool,
+				Computed: true, // Set on refresh when the line was dropped outside Terraform
+			},
         },
 	}
 }
@@ -130,8 +187,10 @@ func resourceLine() *schema.Resource {
 func loadLinesCache(ctx context.Context, m interface{}) error {
 	var err error
 	db := getDB(m)
-	loadOnce.Do(func() {
+	loadLinesOnce.Do(func() {
+		cachedLinesMu.Lock()
 		cachedLines = make(map[int64]*Line)
+		cachedLinesMu.Unlock()
 
 		rows, queryErr := db.QueryContext(ctx, queryLoadLines)
 		if queryErr != nil {
@@ -141,29 +200,69 @@ func loadLinesCache(ctx context.Context, m interface{}) error {
 		defer rows.Close()
 
 		for rows.Next() {
-			var line Line
-			var description, extendedInfo, externalLink, securityGroup, department sql.NullString
-			var securityGroupID sql.NullInt64
-			var deptID sql.NullInt64
-			if scanErr := rows.Scan(&line.Line_Id, &description, &extendedInfo, &externalLink, &securityGroupID, &securityGroup, &deptID, &department); scanErr != nil {
+			line, scanErr := scanLine(rows)
+			if scanErr != nil {
 				err = scanErr
 				return
 			}
-			line.Description = nullableStringToString(description)
-			line.ExtendedInfo = nullableStringToString(extendedInfo)
-			line.ExternalLink = nullableStringToString(externalLink)
-			line.SecurityGroup = nullableStringToString(securityGroup)
-			line.SecurityGroup_Id = nullableInt64ToInt64(securityGroupID)
-			line.Dept_Id = nullableInt64ToInt64(deptID)
-			line.Department = nullableStringToString(department)
-			cachedLines[line.Line_Id] = &line
+			cacheLine(line)
 		}
 	})
 	return err
 }
 
-func resourceLineCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func cachedLine(id int64) (*Line, bool) {
+	cachedLinesMu.RLock()
+	defer cachedLinesMu.RUnlock()
+	line, ok := cachedLines[id]
+	return line, ok
+}
+
+func cacheLine(line *Line) {
+	cachedLinesMu.Lock()
+	defer cachedLinesMu.Unlock()
+	cachedLines[line.Line_Id] = line
+}
+
+func uncacheLine(id int64) {
+	cachedLinesMu.Lock()
+	defer cachedLinesMu.Unlock()
+	delete(cachedLines, id)
+}
+
+func scanLine(row interface{ Scan(...interface{}) error }) (*Line, error) {
+	var line Line
+	var description, extendedInfo, externalLink, securityGroup, department sql.NullString
+	var securityGroupID sql.NullInt64
+	var deptID sql.NullInt64
+	if err := row.Scan(&line.Line_Id, &description, &extendedInfo, &externalLink, &securityGroupID, &securityGroup, &deptID, &department); err != nil {
+		return nil, err
+	}
+	line.Description = nullableStringToString(description)
+	line.ExtendedInfo = nullableStringToString(extendedInfo)
+	line.ExternalLink = nullableStringToString(externalLink)
+	line.SecurityGroup = nullableStringToString(securityGroup)
+	line.SecurityGroup_Id = nullableInt64ToInt64(securityGroupID)
+	line.Dept_Id = nullableInt64ToInt64(deptID)
+	line.Department = nullableStringToString(department)
+	return &line, nil
+}
+
+// getLine reads a single line straight from Prod_Lines_Base, including soft-deleted ones,
+// and caches it unless it has been dropped.
+func getLine(ctx context.Context, m interface{}, id int64) (*Line, error) {
 	db := getDB(m)
+	line, err := scanLine(db.QueryRowContext(ctx, queryGetLine, sql.Named("param_pl_id", id)))
+	if err != nil {
+		return nil, err
+	}
+	if line.Description != lineDeletedDesc {
+		cacheLine(line)
+	}
+	return line, nil
+}
+
+func resourceLineCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if err := loadLinesCache(ctx, m); err != nil {
 		return diag.FromErr(err)
 	}
@@ -178,23 +277,29 @@ func resourceLineCreate(ctx context.Context, d *schema.ResourceData, m interface
 	var outPLID sql.NullInt64
 	var line_id int64
 
-	_, err := db.ExecContext(ctx, queryCreateLine,
-		sql.Named("return_value", sql.Out{Dest: &returnValue}),
-		sql.Named("param_dept_id", dept_id),
-		sql.Named("param_pl_desc", description),
-		sql.Named("param_ext_link", externalLink),
-		sql.Named("param_ext_info", extendedInfo),
-		sql.Named("param_group_id", sg_id),
-		sql.Named("param_user_id", userId),
-		sql.Named("out_PL_Id", sql.Out{Dest: &outPLID}),
-	)
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateLine,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_dept_id", dept_id),
+			sql.Named("param_pl_desc", description),
+			sql.Named("param_ext_link", externalLink),
+			sql.Named("param_ext_info", extendedInfo),
+			sql.Named("param_group_id", sg_id),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_PL_Id", sql.Out{Dest: &outPLID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 || outPLID.Int64 == 0 {
+			return fmt.Errorf(
+				"stored procedure returned failure status: return_value=%v, outPLID.Valid=%v, outPLID=%v",
+				returnValue.Int64, outPLID.Valid, outPLID.Int64)
+		}
+		return nil
+	})
 	if err != nil {
-		return diag.FromErr(fmt.Errorf("failed to execute create logic: %w", err))
-	}
-	if returnValue.Int64 != 0 || outPLID.Int64 == 0 {
-		return diag.FromErr(fmt.Errorf(
-			"stored procedure returned failure status: return_value=%v, outPLID.Valid=%v, outPLID=%v",
-			returnValue.Int64, outPLID.Valid, outPLID.Int64))
+		return diag.FromErr(err)
 	}
 
 	line_id = nullableInt64ToInt64(outPLID)
@@ -210,12 +315,24 @@ func resourceLineRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	}
 
 	id := int64(d.Get("line_id").(int))
-	line, ok := cachedLines[id]
+	line, ok := cachedLine(id)
 	if !ok {
-		d.SetId("")
-		return nil
+		var err error
+		line, err = getLine(ctx, m, id)
+		if err == sql.ErrNoRows {
+			d.SetId("")
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if line.Description == lineDeletedDesc {
+		return resourceLineSoftDeleted(d, line)
 	}
 
+	d.Set("soft_deleted", false)
 	d.Set("id", id)
 	d.Set("description", line.Description)
 	d.Set("department", line.Department)
@@ -227,11 +344,48 @@ func resourceLineRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	return nil
 }
 
+// resourceLineSoftDeleted reports a line that was dropped outside Terraform. Without
+// restore_if_deleted the line leaves state and is recreated under a new PL_Id; with it, the
+// line stays in state so the next apply brings the original PL_Id back.
+func resourceLineSoftDeleted(d *schema.ResourceData, line *Line) diag.Diagnostics {
+	if !d.Get("restore_if_deleted").(bool) {
+		d.SetId("")
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("Line %d was soft-deleted outside Terraform", line.Line_Id),
+			Detail: "The line is marked '<PL Deleted>' in Prod_Lines_Base and will be recreated with a new PL_Id. " +
+				"History tied to the old PL_Id stays with the deleted line. Set restore_if_deleted = true to restore it instead.",
+		}}
+	}
+
+	d.Set("soft_deleted", true)
+	d.Set("description", line.Description)
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  fmt.Sprintf("Line %d was soft-deleted outside Terraform", line.Line_Id),
+		Detail:   "The line is marked '<PL Deleted>' in Prod_Lines_Base and will be restored under its original PL_Id on the next apply.",
+	}}
+}
+
+func resourceLineCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && d.Get("soft_deleted").(bool) {
+		if err := d.SetNew("soft_deleted", false); err != nil {
+			return err
+		}
+	}
+	return customizeDeletionProtection(ctx, d, m)
+}
+
 func resourceLineUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	db := getDB(m)
 	if err := loadLinesCache(ctx, m); err != nil {
 		return diag.FromErr(err)
 	}
+	if softDeleted, _ := d.GetChange("soft_deleted"); softDeleted.(bool) {
+		return resourceLineRestore(ctx, d, m)
+	}
+	if onlyProviderAttributesChanged(d) {
+		return nil
+	}
 	description := d.Get("description").(string)
 	dept_id := int64(d.Get("department_id").(int))
 	extendedInfo := d.Get("extended_info").(string)
@@ -242,47 +396,110 @@ func resourceLineUpdate(ctx context.Context, d *schema.ResourceData, m interface
 	var returnValue sql.NullInt64
 	var outPLID sql.NullInt64
 
-	_, err := db.ExecContext(ctx, queryUpdateLine,
-		sql.Named("return_value", sql.Out{Dest: &returnValue}),
-		sql.Named("param_dept_id", dept_id),
-		sql.Named("param_pl_desc", description),
-		sql.Named("param_ext_link", externalLink),
-		sql.Named("param_ext_info", extendedInfo),
-		sql.Named("param_group_id", sg_id),
-		sql.Named("param_user_id", userId),
-		sql.Named("out_PL_Id", sql.Out{Dest: &outPLID}),
-	)
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanLine(tx.QueryRowContext(ctx, queryGetLineForUpdate,
+			sql.Named("param_pl_id", int64(d.Get("line_id").(int)))))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("line %s no longer exists", d.Id())
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "line", map[string]interface{}{
+			"description":       current.Description,
+			"department_id":     current.Dept_Id,
+			"extended_info":     current.ExtendedInfo,
+			"external_link":     current.ExternalLink,
+			"security_group_id": current.SecurityGroup_Id,
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdateLine,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_dept_id", dept_id),
+			sql.Named("param_pl_desc", description),
+			sql.Named("param_ext_link", externalLink),
+			sql.Named("param_ext_info", extendedInfo),
+			sql.Named("param_group_id", sg_id),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_PL_Id", sql.Out{Dest: &outPLID}),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	uncacheLine(int64(d.Get("line_id").(int)))
+	return resourceLineRead(ctx, d, m)
+}
+
+func resourceLineRestore(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id := int64(d.Get("line_id").(int))
+	sg_id := int64(d.Get("security_group_id").(int))
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, queryRestoreLine,
+			sql.Named("param_pl_id", id),
+			sql.Named("param_dept_id", int64(d.Get("department_id").(int))),
+			sql.Named("param_pl_desc", d.Get("description").(string)),
+			sql.Named("param_ext_info", stringToNullString(d.Get("extended_info").(string))),
+			sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+			sql.Named("param_group_id", sql.NullInt64{Int64: sg_id, Valid: sg_id > 0}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to restore line %d: %w", id, err)
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return fmt.Errorf("line %d is no longer soft-deleted; refresh and try again", id)
+		}
+		return nil
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	uncacheLine(id)
 	return resourceLineRead(ctx, d, m)
 }
 
 func resourceLineDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	fmt.Println("Start of line delete")
+	if err := checkDeletionProtection(d, "line"); err != nil {
+		return diag.FromErr(err)
+	}
 	if err := loadLinesCache(ctx, m); err != nil {
 		return diag.FromErr(err)
 	}
-	db := getDB(m)
 	id := int64(d.Get("line_id").(int))
 	userId := 1
 
 	var returnValue int	
-	_, err := db.ExecContext(ctx, queryDeleteLine,
-		sql.Named("return_value", sql.Out{Dest: &returnValue}),
-		sql.Named("param_pl_id", id),
-		sql.Named("param_user_id", userId),
-	)
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteLine,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_pl_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	if returnValue != 0 {
-		return diag.FromErr(fmt.Errorf("stored procedure returned failure status: %d", returnValue))
-	}
 
-	delete(cachedLines, id)
+	uncacheLine(id)
 	d.SetId("")
-	fmt.Println("End of line delete. pl_id: %d", id)
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type Product struct {
+	Prod_Id              int64
+	Code                 string
+	Description          string
+	Family_Id            int64
+	ExtendedInfo         string
+	EventEsignatureLevel int64
+}
+
+const (
+	queryGetProduct = `
+		SELECT	PB.Prod_Id, PB.Prod_Code, PB.Prod_Desc, PB.Product_Family_Id, PB.Extended_Info,
+				PB.Event_Esignature_Level
+		FROM dbo.Products_Base AS PB
+		WHERE PB.Prod_Id = @param_prod_id;
+		`
+
+	queryGetProductForUpdate = `
+		SELECT	PB.Prod_Id, PB.Prod_Code, PB.Prod_Desc, PB.Product_Family_Id, PB.Extended_Info,
+				PB.Event_Esignature_Level
+		FROM dbo.Products_Base AS PB WITH (UPDLOCK, HOLDLOCK)
+		WHERE PB.Prod_Id = @param_prod_id;
+		`
+
+	queryGetProductIdByCode = `
+		SELECT PB.Prod_Id
+		FROM dbo.Products_Base AS PB
+		WHERE PB.Prod_Code = @param_prod_code;
+		`
+
+	// spEM_IEImportProdFamily creates the product inside the family named by description.
+	queryCreateProduct = `
+		SET XACT_ABORT ON;
+
+		DECLARE @family_desc		NVARCHAR(100);
+
+		SET @family_desc = (
+			SELECT PF.Product_Family_Desc FROM dbo.Product_Family AS PF WHERE PF.Product_Family_Id = @param_family_id);
+
+		IF @family_desc IS NULL
+			THROW 50000, 'product family not found', 1;
+
+		IF EXISTS (SELECT 1 FROM dbo.Products_Base AS PB WHERE PB.Prod_Code = @param_prod_code)
+			THROW 50000, 'a product with this code already exists; import it instead', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportProdFamily]
+				@Prod_Code = @param_prod_code,
+				@Prod_Desc = @param_prod_desc,
+				@Comment1 = NULL,
+				@Product_Family_Desc = @family_desc,
+				@Comment2 = NULL,
+				@EventEsigLevel = @param_event_esig_level,
+				@ProductEsigLevel = NULL,
+				@IsSerialized = NULL,
+				@User_Id = @param_user_id;
+
+		SET @out_Prod_Id = (
+			SELECT PB.Prod_Id FROM dbo.Products_Base AS PB WHERE PB.Prod_Code = @param_prod_code);
+		`
+
+	queryUpdateProduct = `
+		UPDATE dbo.Products_Base SET
+			Prod_Code = @param_prod_code,
+			Prod_Desc = @param_prod_desc,
+			Product_Family_Id = @param_family_id,
+			Extended_Info = @param_ext_info,
+			Event_Esignature_Level = @param_event_esig_level
+		WHERE Prod_Id = @param_prod_id;
+		`
+
+	queryDeleteProduct = `
+		EXEC	@return_value = [dbo].[spEM_DropProduct]
+				@Prod_Id = @param_prod_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceProduct() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceProductCreate,
+		ReadContext:   resourceProductRead,
+		UpdateContext: resourceProductUpdate,
+		DeleteContext: resourceProductDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProductImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"product_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"code": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Prod_Code.",
+				ValidateFunc: validation.StringLenBetween(1, 50),
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Prod_Desc.",
+				ValidateFunc: validation.StringLenBetween(1, 50),
+			},
+			"family_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"extended_info": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"event_esignature_level": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "0 for none, 1 for user, 2 for approver.",
+				ValidateFunc: validation.IntBetween(0, 2),
+			},
+		},
+	}
+}
+
+func scanProduct(row interface{ Scan(...interface{}) error }) (*Product, error) {
+	var product Product
+	var code, description, extendedInfo sql.NullString
+	var familyID, eventEsigLevel sql.NullInt64
+	if err := row.Scan(&product.Prod_Id, &code, &description, &familyID, &extendedInfo, &eventEsigLevel); err != nil {
+		return nil, err
+	}
+	product.Code = nullableStringToString(code)
+	product.Description = nullableStringToString(description)
+	product.Family_Id = nullableIdToInt64(familyID)
+	product.ExtendedInfo = nullableStringToString(extendedInfo)
+	product.EventEsignatureLevel = nullableIdToInt64(eventEsigLevel)
+	return &product, nil
+}
+
+// scanProductCodes collects a single Prod_Code column, for the membership resources.
+func scanProductCodes(rows *sql.Rows, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes := make([]interface{}, 0)
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, rows.Err()
+}
+
+func execUpdateProduct(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, id int64) error {
+	_, err := tx.ExecContext(ctx, queryUpdateProduct,
+		sql.Named("param_prod_id", id),
+		sql.Named("param_prod_code", d.Get("code").(string)),
+		sql.Named("param_prod_desc", d.Get("description").(string)),
+		sql.Named("param_family_id", int64(d.Get("family_id").(int))),
+		sql.Named("param_ext_info", stringToNullString(d.Get("extended_info").(string))),
+		sql.Named("param_event_esig_level", idToNullInt64(int64(d.Get("event_esignature_level").(int)))),
+	)
+	return err
+}
+
+func resourceProductCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	var outProdID sql.NullInt64
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateProduct,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_prod_code", d.Get("code").(string)),
+			sql.Named("param_prod_desc", d.Get("description").(string)),
+			sql.Named("param_family_id", int64(d.Get("family_id").(int))),
+			sql.Named("param_event_esig_level", int64ToString(int64(d.Get("event_esignature_level").(int)))),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_Prod_Id", sql.Out{Dest: &outProdID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 || !outProdID.Valid {
+			return fmt.Errorf(
+				"stored procedure returned failure status: return_value=%v, outProdID.Valid=%v",
+				returnValue.Int64, outProdID.Valid)
+		}
+		return execUpdateProduct(ctx, tx, d, outProdID.Int64)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("product_id", int(outProdID.Int64))
+	d.SetId(int64ToString(outProdID.Int64))
+	return resourceProductRead(ctx, d, m)
+}
+
+func resourceProductRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	product, err := scanProduct(db.QueryRowContext(ctx, queryGetProduct, sql.Named("param_prod_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("product_id", product.Prod_Id)
+	d.Set("code", product.Code)
+	d.Set("description", product.Description)
+	d.Set("family_id", product.Family_Id)
+	d.Set("extended_info", product.ExtendedInfo)
+	d.Set("event_esignature_level", product.EventEsignatureLevel)
+	return nil
+}
+
+func resourceProductUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanProduct(tx.QueryRowContext(ctx, queryGetProductForUpdate, sql.Named("param_prod_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "product", map[string]interface{}{
+			"code":                   current.Code,
+			"description":            current.Description,
+			"family_id":              current.Family_Id,
+			"extended_info":          current.ExtendedInfo,
+			"event_esignature_level": current.EventEsignatureLevel,
+		}); err != nil {
+			return err
+		}
+		return execUpdateProduct(ctx, tx, d, id)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceProductRead(ctx, d, m)
+}
+
+func resourceProductDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteProduct,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_prod_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceProductImport accepts the product code.
+func resourceProductImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetProductIdByCode,
+		sql.Named("param_prod_code", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	// A NULL unit means the product's default characteristic for the property.
+	queryGetProductCharacteristic = `
+		IF @param_pu_id IS NULL
+			SELECT PCD.Char_Id
+			FROM dbo.Product_Characteristic_Defaults AS PCD
+			WHERE PCD.Prod_Id = @param_prod_id AND PCD.Prop_Id = @param_prop_id;
+		ELSE
+			SELECT PUC.Char_Id
+			FROM dbo.PU_Characteristics AS PUC
+			WHERE PUC.PU_Id = @param_pu_id AND PUC.Prod_Id = @param_prod_id AND PUC.Prop_Id = @param_prop_id;
+		`
+
+	queryGetProductCharacteristicForUpdate = `
+		IF @param_pu_id IS NULL
+			SELECT PCD.Char_Id
+			FROM dbo.Product_Characteristic_Defaults AS PCD WITH (UPDLOCK, HOLDLOCK)
+			WHERE PCD.Prod_Id = @param_prod_id AND PCD.Prop_Id = @param_prop_id;
+		ELSE
+			SELECT PUC.Char_Id
+			FROM dbo.PU_Characteristics AS PUC WITH (UPDLOCK, HOLDLOCK)
+			WHERE PUC.PU_Id = @param_pu_id AND PUC.Prod_Id = @param_prod_id AND PUC.Prop_Id = @param_prop_id;
+		`
+
+	queryGetProductCharacteristicIdsByPath = `
+		SELECT	PUB.PU_Id, PB.Prod_Id, PP.Prop_Id
+		FROM dbo.Products_Base AS PB
+		CROSS JOIN dbo.Product_Properties AS PP
+		LEFT JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Desc = @param_pl_desc
+		LEFT JOIN dbo.Prod_Units_Base AS PUB ON PUB.PL_Id = PLB.PL_Id AND PUB.PU_Desc = @param_pu_desc
+		WHERE PB.Prod_Code = @param_prod_code
+		AND PP.Prop_Desc = @param_prop_desc
+		AND (@param_pu_desc IS NULL OR PUB.PU_Id IS NOT NULL);
+		`
+
+	querySetProductCharacteristic = `
+		SET XACT_ABORT ON;
+
+		DECLARE @pl_desc			NVARCHAR(50),
+				@pu_desc			NVARCHAR(50),
+				@prod_code			NVARCHAR(500),
+				@prop_desc			NVARCHAR(80),
+				@char_desc			NVARCHAR(500);
+
+		SET @prod_code = (
+			SELECT PB.Prod_Code FROM dbo.Products_Base AS PB WHERE PB.Prod_Id = @param_prod_id);
+
+		IF @prod_code IS NULL
+			THROW 50000, 'product not found', 1;
+
+		SET @prop_desc = (
+			SELECT PP.Prop_Desc FROM dbo.Product_Properties AS PP WHERE PP.Prop_Id = @param_prop_id);
+
+		IF @prop_desc IS NULL
+			THROW 50000, 'product property not found', 1;
+
+		SET @char_desc = (
+			SELECT C.Char_Desc FROM dbo.Characteristics AS C
+			WHERE C.Char_Id = @param_char_id AND C.Prop_Id = @param_prop_id);
+
+		IF @char_desc IS NULL
+			THROW 50000, 'characteristic not found under this property', 1;
+
+		IF @param_pu_id IS NULL
+		BEGIN
+			EXEC	@return_value = [dbo].[spEM_IEImportDefaultCharacteristic]
+					@ProductCode = @prod_code,
+					@ProductProperty = @prop_desc,
+					@Characteristic = @char_desc,
+					@UserId = @param_user_id;
+		END
+		ELSE
+		BEGIN
+			SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc
+			FROM dbo.Prod_Units_Base AS PUB
+			JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+			WHERE PUB.PU_Id = @param_pu_id;
+
+			IF @pu_desc IS NULL
+				THROW 50000, 'unit not found', 1;
+
+			EXEC	@return_value = [dbo].[spEM_IEImportProductCharacteristics]
+					@PL_Desc = @pl_desc,
+					@PU_Desc = @pu_desc,
+					@Prod_Code = @prod_code,
+					@Prop_Desc = @prop_desc,
+					@Char_Desc = @char_desc,
+					@User_Id = @param_user_id,
+					@Trans_Id = NULL;
+		END
+		`
+
+	// The PA Administrator's removals, so unassigning is audited like the import that assigned it.
+	queryDeleteProductCharacteristic = `
+		IF @param_pu_id IS NULL
+			EXEC	@return_value = [dbo].[spEM_DropDefaultCharacteristic]
+					@Prod_Id = @param_prod_id,
+					@Prop_Id = @param_prop_id,
+					@User_Id = @param_user_id;
+		ELSE
+			EXEC	@return_value = [dbo].[spEM_DropUnitCharacteristic]
+					@PU_Id = @param_pu_id,
+					@Prod_Id = @param_prod_id,
+					@Prop_Id = @param_prop_id,
+					@User_Id = @param_user_id;
+		`
+)
+
+// resourceProductCharacteristic assigns a product the characteristic it uses for one property,
+// either on a unit or, with unit_id unset, as the product's default.
+func resourceProductCharacteristic() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceProductCharacteristicCreate,
+		ReadContext:   resourceProductCharacteristicRead,
+		UpdateContext: resourceProductCharacteristicUpdate,
+		DeleteContext: resourceProductCharacteristicDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProductCharacteristicImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"unit_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Unit the assignment applies to. Unset for the product's default characteristic.",
+			},
+			"product_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"property_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"characteristic_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+		},
+	}
+}
+
+// parseProductCharacteristicId splits the "unit/product/property" ID; unit is 0 for defaults.
+func parseProductCharacteristicId(id string) (unitID, prodID, propID int64, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("unexpected product characteristic ID %q", id)
+	}
+	if unitID, err = stringToInt64(parts[0]); err != nil {
+		return
+	}
+	if prodID, err = stringToInt64(parts[1]); err != nil {
+		return
+	}
+	propID, err = stringToInt64(parts[2])
+	return
+}
+
+func execSetProductCharacteristic(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) error {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	_, err := tx.ExecContext(ctx, querySetProductCharacteristic,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_pu_id", idToNullInt64(int64(d.Get("unit_id").(int)))),
+		sql.Named("param_prod_id", int64(d.Get("product_id").(int))),
+		sql.Named("param_prop_id", int64(d.Get("property_id").(int))),
+		sql.Named("param_char_id", int64(d.Get("characteristic_id").(int))),
+		sql.Named("param_user_id", userId),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to assign characteristic: %w", err)
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceProductCharacteristicCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		return execSetProductCharacteristic(ctx, tx, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d/%d", d.Get("unit_id").(int), d.Get("product_id").(int), d.Get("property_id").(int)))
+	return resourceProductCharacteristicRead(ctx, d, m)
+}
+
+func resourceProductCharacteristicRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	unitID, prodID, propID, err := parseProductCharacteristicId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var charID sql.NullInt64
+	err = db.QueryRowContext(ctx, queryGetProductCharacteristic,
+		sql.Named("param_pu_id", idToNullInt64(unitID)),
+		sql.Named("param_prod_id", prodID),
+		sql.Named("param_prop_id", propID),
+	).Scan(&charID)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("unit_id", unitID)
+	d.Set("product_id", prodID)
+	d.Set("property_id", propID)
+	d.Set("characteristic_id", nullableIdToInt64(charID))
+	return nil
+}
+
+func resourceProductCharacteristicUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unitID, prodID, propID, err := parseProductCharacteristicId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var current sql.NullInt64
+		err := tx.QueryRowContext(ctx, queryGetProductCharacteristicForUpdate,
+			sql.Named("param_pu_id", idToNullInt64(unitID)),
+			sql.Named("param_prod_id", prodID),
+			sql.Named("param_prop_id", propID),
+		).Scan(&current)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product characteristic %s no longer exists", d.Id())
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "product characteristic", map[string]interface{}{
+			"characteristic_id": nullableIdToInt64(current),
+		}); err != nil {
+			return err
+		}
+		return execSetProductCharacteristic(ctx, tx, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceProductCharacteristicRead(ctx, d, m)
+}
+
+func resourceProductCharacteristicDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unitID, prodID, propID, err := parseProductCharacteristicId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	userId := 1
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var returnValue sql.NullInt64
+		_, err := tx.ExecContext(ctx, queryDeleteProductCharacteristic,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_pu_id", idToNullInt64(unitID)),
+			sql.Named("param_prod_id", prodID),
+			sql.Named("param_prop_id", propID),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceProductCharacteristicImport accepts "Product/Property" for a default characteristic
+// and "Line/Unit/Product/Property" for a unit assignment. The product code may contain '/', so
+// an ID with four or more parts is tried as a unit assignment first and, if none matches, as a
+// default characteristic whose code is everything before the property.
+func resourceProductCharacteristicImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	lookup := func(plDesc, puDesc sql.NullString, prodCode, propDesc string) (string, error) {
+		var unitID sql.NullInt64
+		var prodID, propID int64
+		err := getDB(m).QueryRowContext(ctx, queryGetProductCharacteristicIdsByPath,
+			sql.Named("param_pl_desc", plDesc),
+			sql.Named("param_pu_desc", puDesc),
+			sql.Named("param_prod_code", prodCode),
+			sql.Named("param_prop_desc", propDesc),
+		).Scan(&unitID, &prodID, &propID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d/%d/%d", nullableIdToInt64(unitID), prodID, propID), nil
+	}
+
+	var id string
+	if strings.Count(d.Id(), "/") >= 3 {
+		parts, err := splitImportPathAround(d.Id(), 2, "Line", "Unit", "Product", "Property")
+		if err != nil {
+			return nil, err
+		}
+		id, err = lookup(stringToNullString(parts[0]), stringToNullString(parts[1]), parts[2], parts[3])
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+	}
+	if id == "" {
+		parts, err := splitImportPathAround(d.Id(), 0, "Product", "Property")
+		if err != nil {
+			return nil, err
+		}
+		id, err = lookup(sql.NullString{}, sql.NullString{}, parts[0], parts[1])
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+	}
+	if id == "" {
+		return nil, fmt.Errorf("product characteristic %q not found", d.Id())
+	}
+
+	d.SetId(id)
+	return []*schema.ResourceData{d}, nil
+}
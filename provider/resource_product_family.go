@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetProductFamily = `
+		SELECT PF.Product_Family_Desc
+		FROM dbo.Product_Family AS PF
+		WHERE PF.Product_Family_Id = @param_family_id;
+		`
+
+	queryGetProductFamilyForUpdate = `
+		SELECT PF.Product_Family_Desc
+		FROM dbo.Product_Family AS PF WITH (UPDLOCK, HOLDLOCK)
+		WHERE PF.Product_Family_Id = @param_family_id;
+		`
+
+	queryGetProductFamilyIdByDesc = `
+		SELECT PF.Product_Family_Id
+		FROM dbo.Product_Family AS PF
+		WHERE PF.Product_Family_Desc = @param_family_desc;
+		`
+
+	queryCreateProductFamily = `
+		EXEC	@return_value = [dbo].[spEM_CreateProductFamily]
+				@Product_Family_Desc = @param_family_desc,
+				@User_Id = @param_user_id,
+				@Product_Family_Id = @out_family_id OUTPUT;
+		`
+
+	queryUpdateProductFamily = `
+		UPDATE dbo.Product_Family SET
+			Product_Family_Desc = @param_family_desc
+		WHERE Product_Family_Id = @param_family_id;
+		`
+
+	queryDeleteProductFamily = `
+		EXEC	@return_value = [dbo].[spEM_DropProductFamily]
+				@Product_Family_Id = @param_family_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceProductFamily() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceProductFamilyCreate,
+		ReadContext:   resourceProductFamilyRead,
+		UpdateContext: resourceProductFamilyUpdate,
+		DeleteContext: resourceProductFamilyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProductFamilyImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"family_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateTitle(),
+			},
+		},
+	}
+}
+
+func resourceProductFamilyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	var familyID sql.NullInt64
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateProductFamily,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_family_desc", d.Get("description").(string)),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_family_id", sql.Out{Dest: &familyID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 || !familyID.Valid {
+			return fmt.Errorf("stored procedure returned failure status: %d or null ID", returnValue.Int64)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("family_id", int(familyID.Int64))
+	d.SetId(int64ToString(familyID.Int64))
+	return resourceProductFamilyRead(ctx, d, m)
+}
+
+func resourceProductFamilyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var description sql.NullString
+	err = db.QueryRowContext(ctx, queryGetProductFamily, sql.Named("param_family_id", id)).Scan(&description)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("family_id", id)
+	d.Set("description", nullableStringToString(description))
+	return nil
+}
+
+func resourceProductFamilyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var current sql.NullString
+		err := tx.QueryRowContext(ctx, queryGetProductFamilyForUpdate, sql.Named("param_family_id", id)).Scan(&current)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product family %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "product family", map[string]interface{}{
+			"description": nullableStringToString(current),
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdateProductFamily,
+			sql.Named("param_family_id", id),
+			sql.Named("param_family_desc", d.Get("description").(string)),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceProductFamilyRead(ctx, d, m)
+}
+
+func resourceProductFamilyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteProductFamily,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_family_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceProductFamilyImport accepts the family description.
+func resourceProductFamilyImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetProductFamilyIdByDesc,
+		sql.Named("param_family_desc", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product family %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetProductGroup = `
+		SELECT PG.Product_Grp_Desc
+		FROM dbo.Product_Groups AS PG
+		WHERE PG.Product_Grp_Id = @param_group_id;
+		`
+
+	queryGetProductGroupForUpdate = `
+		SELECT PG.Product_Grp_Desc
+		FROM dbo.Product_Groups AS PG WITH (UPDLOCK, HOLDLOCK)
+		WHERE PG.Product_Grp_Id = @param_group_id;
+		`
+
+	queryGetProductGroupIdByDesc = `
+		SELECT PG.Product_Grp_Id
+		FROM dbo.Product_Groups AS PG
+		WHERE PG.Product_Grp_Desc = @param_group_desc;
+		`
+
+	queryLoadProductGroupMembers = `
+		SELECT PB.Prod_Code
+		FROM dbo.Product_Group_Data AS PGD
+		JOIN dbo.Products_Base AS PB ON PB.Prod_Id = PGD.Prod_Id
+		WHERE PGD.Product_Grp_Id = @param_group_id
+		ORDER BY PB.Prod_Code;
+		`
+
+	// spEM_IEImportProductGroups creates the group on first use and adds one product to it.
+	queryAddProductGroupMember = `
+		EXEC	@return_value = [dbo].[spEM_IEImportProductGroups]
+				@Product_Grp_Desc = @param_group_desc,
+				@Prod_Code = @param_prod_code,
+				@Comment = NULL,
+				@User_Id = @param_user_id;
+		`
+
+	// spEM_DropProductGroupData is the PA Administrator's removal, so it is audited the same way.
+	queryRemoveProductGroupMember = `
+		SET XACT_ABORT ON;
+
+		DECLARE @prod_id INT = (SELECT PB.Prod_Id FROM dbo.Products_Base AS PB WHERE PB.Prod_Code = @param_prod_code);
+
+		IF @prod_id IS NULL
+			THROW 50000, 'product not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_DropProductGroupData]
+				@Product_Grp_Id = @param_group_id,
+				@Prod_Id = @prod_id,
+				@User_Id = @param_user_id;
+		`
+
+	queryUpdateProductGroup = `
+		UPDATE dbo.Product_Groups SET
+			Product_Grp_Desc = @param_group_desc
+		WHERE Product_Grp_Id = @param_group_id;
+		`
+
+	queryDeleteProductGroup = `
+		EXEC	@return_value = [dbo].[spEM_DropProductGroup]
+				@Product_Grp_Id = @param_group_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceProductGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceProductGroupCreate,
+		ReadContext:   resourceProductGroupRead,
+		UpdateContext: resourceProductGroupUpdate,
+		DeleteContext: resourceProductGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProductGroupImport,
+		},
+		CustomizeDiff: resourceProductGroupCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Product_Grp_Desc.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"product_codes": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Every product in the group. Members added outside Terraform are removed.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringLenBetween(1, 50),
+				},
+			},
+		},
+	}
+}
+
+// resourceProductGroupCustomizeDiff reports missing products at plan time. Codes that are not
+// known yet, such as those of products created in the same apply, are checked at apply time.
+func resourceProductGroupCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if m == nil || !d.HasChange("product_codes") || !d.NewValueKnown("product_codes") {
+		return nil
+	}
+	return checkProductsExist(ctx, getDB(m), d.Get("product_codes").(*schema.Set))
+}
+
+// checkProductsExist reports every missing product code at once rather than failing on the first.
+func checkProductsExist(ctx context.Context, q rowQuerier, codes *schema.Set) error {
+	var missing []string
+	for _, code := range codes.List() {
+		var id int64
+		err := q.QueryRowContext(ctx, queryGetProductIdByCode, sql.Named("param_prod_code", code.(string))).Scan(&id)
+		if err == sql.ErrNoRows {
+			missing = append(missing, code.(string))
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("products not found: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// syncProductGroupMembers adds and removes members so the group matches desired exactly.
+// groupID is 0 while the group does not exist yet.
+func syncProductGroupMembers(ctx context.Context, tx *sql.Tx, groupID int64, groupDesc string, desired *schema.Set) error {
+	userId := 1
+
+	if err := checkProductsExist(ctx, tx, desired); err != nil {
+		return err
+	}
+
+	codes, err := scanProductCodes(tx.QueryContext(ctx, queryLoadProductGroupMembers, sql.Named("param_group_id", groupID)))
+	if err != nil {
+		return err
+	}
+	current := schema.NewSet(schema.HashString, codes)
+
+	for _, code := range current.Difference(desired).List() {
+		var returnValue sql.NullInt64
+		_, err := tx.ExecContext(ctx, queryRemoveProductGroupMember,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_group_id", groupID),
+			sql.Named("param_prod_code", code.(string)),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to remove product %q from group %q: %w", code, groupDesc, err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status %d removing product %q", returnValue.Int64, code)
+		}
+	}
+
+	for _, code := range desired.Difference(current).List() {
+		var returnValue sql.NullInt64
+		_, err := tx.ExecContext(ctx, queryAddProductGroupMember,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_group_desc", groupDesc),
+			sql.Named("param_prod_code", code.(string)),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add product %q to group %q: %w", code, groupDesc, err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status %d adding product %q", returnValue.Int64, code)
+		}
+	}
+	return nil
+}
+
+func resourceProductGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	description := d.Get("description").(string)
+
+	var groupID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetProductGroupIdByDesc, sql.Named("param_group_desc", description)).Scan(&groupID)
+		if err == nil {
+			return fmt.Errorf("product group %q already exists; import it instead", description)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := syncProductGroupMembers(ctx, tx, 0, description, d.Get("product_codes").(*schema.Set)); err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetProductGroupIdByDesc, sql.Named("param_group_desc", description)).Scan(&groupID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product group %q was not created", description)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("group_id", int(groupID))
+	d.SetId(int64ToString(groupID))
+	return resourceProductGroupRead(ctx, d, m)
+}
+
+func resourceProductGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var description sql.NullString
+	err = db.QueryRowContext(ctx, queryGetProductGroup, sql.Named("param_group_id", id)).Scan(&description)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	codes, err := scanProductCodes(db.QueryContext(ctx, queryLoadProductGroupMembers, sql.Named("param_group_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("group_id", id)
+	d.Set("description", nullableStringToString(description))
+	d.Set("product_codes", codes)
+	return nil
+}
+
+func resourceProductGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	description := d.Get("description").(string)
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		if d.HasChange("description") {
+			var current sql.NullString
+			err := tx.QueryRowContext(ctx, queryGetProductGroupForUpdate, sql.Named("param_group_id", id)).Scan(&current)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("product group %d no longer exists", id)
+			}
+			if err != nil {
+				return err
+			}
+			if err := checkUnchanged(d, "product group", map[string]interface{}{
+				"description": nullableStringToString(current),
+			}); err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, queryUpdateProductGroup,
+				sql.Named("param_group_id", id),
+				sql.Named("param_group_desc", description),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return syncProductGroupMembers(ctx, tx, id, description, d.Get("product_codes").(*schema.Set))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceProductGroupRead(ctx, d, m)
+}
+
+func resourceProductGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteProductGroup,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_group_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceProductGroupImport accepts the group description.
+func resourceProductGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetProductGroupIdByDesc,
+		sql.Named("param_group_desc", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product group %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type ProductProperty struct {
+	Prop_Id          int64
+	Description      string
+	ExternalLink     string
+	SecurityGroup_Id int64
+}
+
+const (
+	queryGetProductProperty = `
+		SELECT PP.Prop_Id, PP.Prop_Desc, PP.External_Link, PP.Group_Id
+		FROM dbo.Product_Properties AS PP
+		WHERE PP.Prop_Id = @param_prop_id;
+		`
+
+	queryGetProductPropertyForUpdate = `
+		SELECT PP.Prop_Id, PP.Prop_Desc, PP.External_Link, PP.Group_Id
+		FROM dbo.Product_Properties AS PP WITH (UPDLOCK, HOLDLOCK)
+		WHERE PP.Prop_Id = @param_prop_id;
+		`
+
+	queryGetProductPropertyDesc = `
+		SELECT PP.Prop_Desc
+		FROM dbo.Product_Properties AS PP
+		WHERE PP.Prop_Id = @param_prop_id;
+		`
+
+	queryGetProductPropertyIdByDesc = `
+		SELECT PP.Prop_Id
+		FROM dbo.Product_Properties AS PP
+		WHERE PP.Prop_Desc = @param_prop_desc;
+		`
+
+	queryCreateProductProperty = `
+		SET XACT_ABORT ON;
+
+		DECLARE @sg_desc			NVARCHAR(50);
+
+		IF EXISTS (SELECT 1 FROM dbo.Product_Properties AS PP WHERE PP.Prop_Desc = @param_prop_desc)
+			THROW 50000, 'a product property with this description already exists; import it instead', 1;
+
+		SET @sg_desc = (
+			SELECT SG.Group_Desc FROM dbo.Security_Groups AS SG WHERE SG.Group_Id = @param_group_id);
+
+		EXEC	@return_value = [dbo].[spEM_IEImportProductProperties]
+				@Prop_Desc = @param_prop_desc,
+				@External_Link = @param_ext_link,
+				@Group_Desc = @sg_desc,
+				@User_Id = @param_user_id;
+
+		SET @out_Prop_Id = (
+			SELECT PP.Prop_Id FROM dbo.Product_Properties AS PP WHERE PP.Prop_Desc = @param_prop_desc);
+		`
+
+	queryUpdateProductProperty = `
+		UPDATE dbo.Product_Properties SET
+			Prop_Desc = @param_prop_desc,
+			External_Link = @param_ext_link,
+			Group_Id = @param_group_id
+		WHERE Prop_Id = @param_prop_id;
+		`
+
+	queryDeleteProductProperty = `
+		EXEC	@return_value = [dbo].[spEM_DropProp]
+				@Prop_Id = @param_prop_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceProductProperty() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceProductPropertyCreate,
+		ReadContext:   resourceProductPropertyRead,
+		UpdateContext: resourceProductPropertyUpdate,
+		DeleteContext: resourceProductPropertyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceProductPropertyImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"property_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Prop_Desc.",
+				ValidateFunc: validation.StringLenBetween(1, 50),
+			},
+			"external_link": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"security_group_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func scanProductProperty(row interface{ Scan(...interface{}) error }) (*ProductProperty, error) {
+	var property ProductProperty
+	var description, externalLink sql.NullString
+	var securityGroupID sql.NullInt64
+	if err := row.Scan(&property.Prop_Id, &description, &externalLink, &securityGroupID); err != nil {
+		return nil, err
+	}
+	property.Description = nullableStringToString(description)
+	property.ExternalLink = nullableStringToString(externalLink)
+	property.SecurityGroup_Id = nullableIdToInt64(securityGroupID)
+	return &property, nil
+}
+
+func resourceProductPropertyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	var outPropID sql.NullInt64
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateProductProperty,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_prop_desc", d.Get("description").(string)),
+			sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+			sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_Prop_Id", sql.Out{Dest: &outPropID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 || !outPropID.Valid {
+			return fmt.Errorf(
+				"stored procedure returned failure status: return_value=%v, outPropID.Valid=%v",
+				returnValue.Int64, outPropID.Valid)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("property_id", int(outPropID.Int64))
+	d.SetId(int64ToString(outPropID.Int64))
+	return resourceProductPropertyRead(ctx, d, m)
+}
+
+func resourceProductPropertyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	property, err := scanProductProperty(db.QueryRowContext(ctx, queryGetProductProperty, sql.Named("param_prop_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("property_id", property.Prop_Id)
+	d.Set("description", property.Description)
+	d.Set("external_link", property.ExternalLink)
+	d.Set("security_group_id", property.SecurityGroup_Id)
+	return nil
+}
+
+func resourceProductPropertyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanProductProperty(tx.QueryRowContext(ctx, queryGetProductPropertyForUpdate, sql.Named("param_prop_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("product property %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "product property", map[string]interface{}{
+			"description":       current.Description,
+			"external_link":     current.ExternalLink,
+			"security_group_id": current.SecurityGroup_Id,
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdateProductProperty,
+			sql.Named("param_prop_id", id),
+			sql.Named("param_prop_desc", d.Get("description").(string)),
+			sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+			sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceProductPropertyRead(ctx, d, m)
+}
+
+func resourceProductPropertyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteProductProperty,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_prop_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceProductPropertyImport accepts the property description.
+func resourceProductPropertyImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetProductPropertyIdByDesc,
+		sql.Named("param_prop_desc", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("product property %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetReasonCategory = `
+		SELECT ERC.ERC_Desc
+		FROM dbo.Event_Reason_Catagories AS ERC
+		WHERE ERC.ERC_Id = @param_category_id;
+		`
+
+	queryGetReasonCategoryForUpdate = `
+		SELECT ERC.ERC_Desc
+		FROM dbo.Event_Reason_Catagories AS ERC WITH (UPDLOCK, HOLDLOCK)
+		WHERE ERC.ERC_Id = @param_category_id;
+		`
+
+	queryGetReasonCategoryIdByDesc = `
+		SELECT ERC.ERC_Id
+		FROM dbo.Event_Reason_Catagories AS ERC
+		WHERE ERC.ERC_Desc = @param_category_desc;
+		`
+
+	// spEM_IEImportReasonCategory only creates a category while attaching it to a tree node, so a
+	// category on its own is inserted directly.
+	queryCreateReasonCategory = `
+		SET XACT_ABORT ON;
+
+		IF EXISTS (SELECT 1 FROM dbo.Event_Reason_Catagories AS ERC WHERE ERC.ERC_Desc = @param_category_desc)
+			THROW 50000, 'a reason category with this description already exists; import it instead', 1;
+
+		INSERT INTO dbo.Event_Reason_Catagories (ERC_Desc)
+		VALUES (@param_category_desc);
+
+		SET @out_category_id = SCOPE_IDENTITY();
+		`
+
+	queryUpdateReasonCategory = `
+		UPDATE dbo.Event_Reason_Catagories SET
+			ERC_Desc = @param_category_desc
+		WHERE ERC_Id = @param_category_id;
+		`
+
+	// Units also name categories for their OEE buckets, so a category still in use is kept.
+	queryDeleteReasonCategory = `
+		SET XACT_ABORT ON;
+
+		IF EXISTS (SELECT 1 FROM dbo.Event_Reason_Category_Data AS ERCD WHERE ERCD.ERC_Id = @param_category_id)
+			THROW 50000, 'reason category is still attached to reason tree nodes', 1;
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Prod_Units_Base AS PUB
+			WHERE @param_category_id IN (PUB.Downtime_Scheduled_Category, PUB.Downtime_External_Category,
+				PUB.Performance_Downtime_Category, PUB.Non_Productive_Category))
+			THROW 50000, 'reason category is still used by a unit', 1;
+
+		DELETE FROM dbo.Event_Reason_Catagories WHERE ERC_Id = @param_category_id;
+		`
+)
+
+func resourceReasonCategory() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceReasonCategoryCreate,
+		ReadContext:   resourceReasonCategoryRead,
+		UpdateContext: resourceReasonCategoryUpdate,
+		DeleteContext: resourceReasonCategoryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceReasonCategoryImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"category_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "ERC_Desc.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+		},
+	}
+}
+
+func resourceReasonCategoryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var categoryID sql.NullInt64
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateReasonCategory,
+			sql.Named("param_category_desc", d.Get("description").(string)),
+			sql.Named("out_category_id", sql.Out{Dest: &categoryID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if !categoryID.Valid {
+			return fmt.Errorf("reason category %q was not created", d.Get("description").(string))
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("category_id", int(categoryID.Int64))
+	d.SetId(int64ToString(categoryID.Int64))
+	return resourceReasonCategoryRead(ctx, d, m)
+}
+
+func resourceReasonCategoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var description sql.NullString
+	err = db.QueryRowContext(ctx, queryGetReasonCategory, sql.Named("param_category_id", id)).Scan(&description)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("category_id", id)
+	d.Set("description", nullableStringToString(description))
+	return nil
+}
+
+func resourceReasonCategoryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var current sql.NullString
+		err := tx.QueryRowContext(ctx, queryGetReasonCategoryForUpdate, sql.Named("param_category_id", id)).Scan(&current)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("reason category %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "reason category", map[string]interface{}{
+			"description": nullableStringToString(current),
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdateReasonCategory,
+			sql.Named("param_category_id", id),
+			sql.Named("param_category_desc", d.Get("description").(string)),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceReasonCategoryRead(ctx, d, m)
+}
+
+func resourceReasonCategoryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteReasonCategory, sql.Named("param_category_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceReasonCategoryImport accepts the category description.
+func resourceReasonCategoryImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetReasonCategoryIdByDesc,
+		sql.Named("param_category_desc", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reason category %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
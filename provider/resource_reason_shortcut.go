@@ -0,0 +1,392 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// reasonShortcutEventTypes maps event_type to ET_Id.
+var reasonShortcutEventTypes = map[string]int64{
+	"downtime": 2,
+	"waste":    3,
+}
+
+type ReasonShortcut struct {
+	Shortcut_Id int64
+	Unit_Id     int64
+	Name        string
+	EventType   string
+	Amount      int64
+	Location_Id int64
+	ReasonIds   []interface{}
+}
+
+const (
+	queryGetReasonShortcut = `
+		SELECT	RS.RS_Id, RS.PU_Id, RS.Shortcut_Name, RS.ET_Id, RS.Amount_Time, RS.Source_PU_Id,
+				RS.Reason_Level1, RS.Reason_Level2, RS.Reason_Level3, RS.Reason_Level4
+		FROM dbo.Reason_Shortcuts AS RS
+		WHERE RS.RS_Id = @param_shortcut_id;
+		`
+
+	queryGetReasonShortcutForUpdate = `
+		SELECT	RS.RS_Id, RS.PU_Id, RS.Shortcut_Name, RS.ET_Id, RS.Amount_Time, RS.Source_PU_Id,
+				RS.Reason_Level1, RS.Reason_Level2, RS.Reason_Level3, RS.Reason_Level4
+		FROM dbo.Reason_Shortcuts AS RS WITH (UPDLOCK, HOLDLOCK)
+		WHERE RS.RS_Id = @param_shortcut_id;
+		`
+
+	queryGetReasonShortcutIdByName = `
+		SELECT RS.RS_Id
+		FROM dbo.Reason_Shortcuts AS RS
+		WHERE RS.PU_Id = @param_pu_id
+		AND RS.Shortcut_Name = @param_shortcut_name;
+		`
+
+	queryGetReasonShortcutIdByPath = `
+		SELECT RS.RS_Id
+		FROM dbo.Reason_Shortcuts AS RS
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = RS.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PLB.PL_Desc = @param_pl_desc
+		AND PUB.PU_Desc = @param_pu_desc
+		AND RS.Shortcut_Name = @param_shortcut_name;
+		`
+
+	// spEM_IEImportReasonShortcut creates or updates the shortcut named SCName on the unit.
+	queryImportReasonShortcut = `
+		SET XACT_ABORT ON;
+
+		DECLARE @pl_desc			NVARCHAR(100),
+				@pu_desc			NVARCHAR(100),
+				@location_desc		NVARCHAR(100),
+				@r1					NVARCHAR(100),
+				@r2					NVARCHAR(100),
+				@r3					NVARCHAR(100),
+				@r4					NVARCHAR(100);
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc
+		FROM dbo.Prod_Units_Base AS PUB
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+
+		IF @pu_desc IS NULL
+			THROW 50000, 'unit not found', 1;
+
+		IF @param_location_id IS NOT NULL
+		BEGIN
+			SET @location_desc = (
+				SELECT PUB.PU_Desc FROM dbo.Prod_Units_Base AS PUB WHERE PUB.PU_Id = @param_location_id);
+
+			IF @location_desc IS NULL
+				THROW 50000, 'location unit not found', 1;
+		END
+
+		SET @r1 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason1);
+		SET @r2 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason2);
+		SET @r3 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason3);
+		SET @r4 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason4);
+
+		IF @r1 IS NULL
+			OR (@param_reason2 IS NOT NULL AND @r2 IS NULL)
+			OR (@param_reason3 IS NOT NULL AND @r3 IS NULL)
+			OR (@param_reason4 IS NOT NULL AND @r4 IS NULL)
+			THROW 50000, 'event reason not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportReasonShortcut]
+				@LineDesc = @pl_desc,
+				@UnitDesc = @pu_desc,
+				@SCName = @param_shortcut_name,
+				@AmountTime = @param_amount,
+				@LocationDesc = @location_desc,
+				@Type = @param_et_id,
+				@RLevel1 = @r1,
+				@RLevel2 = @r2,
+				@RLevel3 = @r3,
+				@RLevel4 = @r4,
+				@User_Id = @param_user_id;
+		`
+
+	queryRenameReasonShortcut = `
+		UPDATE dbo.Reason_Shortcuts SET
+			Shortcut_Name = @param_shortcut_name
+		WHERE RS_Id = @param_shortcut_id;
+		`
+
+	queryDeleteReasonShortcut = `
+		DELETE FROM dbo.Reason_Shortcuts
+		WHERE RS_Id = @param_shortcut_id;
+		`
+)
+
+func resourceReasonShortcut() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceReasonShortcutCreate,
+		ReadContext:   resourceReasonShortcutRead,
+		UpdateContext: resourceReasonShortcutUpdate,
+		DeleteContext: resourceReasonShortcutDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceReasonShortcutImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"shortcut_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"unit_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Shortcut_Name.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"event_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "downtime or waste.",
+				ValidateFunc: validation.StringInSlice([]string{"downtime", "waste"}, false),
+			},
+			"amount": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Minutes for downtime, quantity for waste.",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"location_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Unit the event is assigned to.",
+			},
+			"reason_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				MaxItems:    reasonTreeDepth,
+				Description: "Reason path, from level 1 down.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+func scanReasonShortcut(row interface{ Scan(...interface{}) error }) (*ReasonShortcut, error) {
+	var shortcut ReasonShortcut
+	var name sql.NullString
+	var etID, amount, locationID sql.NullInt64
+	reasons := make([]sql.NullInt64, reasonTreeDepth)
+	if err := row.Scan(&shortcut.Shortcut_Id, &shortcut.Unit_Id, &name, &etID, &amount, &locationID,
+		&reasons[0], &reasons[1], &reasons[2], &reasons[3]); err != nil {
+		return nil, err
+	}
+	shortcut.Name = nullableStringToString(name)
+	for eventType, id := range reasonShortcutEventTypes {
+		if id == etID.Int64 {
+			shortcut.EventType = eventType
+		}
+	}
+	shortcut.Amount = amount.Int64
+	shortcut.Location_Id = nullableIdToInt64(locationID)
+	shortcut.ReasonIds = []interface{}{}
+	for _, reasonID := range reasons {
+		if !reasonID.Valid {
+			break
+		}
+		shortcut.ReasonIds = append(shortcut.ReasonIds, int(reasonID.Int64))
+	}
+	return &shortcut, nil
+}
+
+func execImportReasonShortcut(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) error {
+	userId := 1
+
+	levels := make([]interface{}, reasonTreeDepth)
+	for i := range levels {
+		levels[i] = sql.NullInt64{}
+	}
+	for i, v := range d.Get("reason_ids").([]interface{}) {
+		levels[i] = int64(v.(int))
+	}
+
+	var returnValue sql.NullInt64
+	_, err := tx.ExecContext(ctx, queryImportReasonShortcut,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_pu_id", int64(d.Get("unit_id").(int))),
+		sql.Named("param_shortcut_name", d.Get("name").(string)),
+		sql.Named("param_amount", int64ToString(int64(d.Get("amount").(int)))),
+		sql.Named("param_location_id", idToNullInt64(int64(d.Get("location_id").(int)))),
+		sql.Named("param_et_id", int64ToString(reasonShortcutEventTypes[d.Get("event_type").(string)])),
+		sql.Named("param_reason1", levels[0]),
+		sql.Named("param_reason2", levels[1]),
+		sql.Named("param_reason3", levels[2]),
+		sql.Named("param_reason4", levels[3]),
+		sql.Named("param_user_id", userId),
+	)
+	if err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceReasonShortcutCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unitID := int64(d.Get("unit_id").(int))
+	name := d.Get("name").(string)
+
+	var shortcutID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetReasonShortcutIdByName,
+			sql.Named("param_pu_id", unitID),
+			sql.Named("param_shortcut_name", name),
+		).Scan(&shortcutID)
+		if err == nil {
+			return fmt.Errorf("unit %d already has a reason shortcut %q; import it instead", unitID, name)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportReasonShortcut(ctx, tx, d); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetReasonShortcutIdByName,
+			sql.Named("param_pu_id", unitID),
+			sql.Named("param_shortcut_name", name),
+		).Scan(&shortcutID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("reason shortcut %q was not created", name)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("shortcut_id", int(shortcutID))
+	d.SetId(int64ToString(shortcutID))
+	return resourceReasonShortcutRead(ctx, d, m)
+}
+
+func resourceReasonShortcutRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	shortcut, err := scanReasonShortcut(db.QueryRowContext(ctx, queryGetReasonShortcut, sql.Named("param_shortcut_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("shortcut_id", shortcut.Shortcut_Id)
+	d.Set("unit_id", shortcut.Unit_Id)
+	d.Set("name", shortcut.Name)
+	d.Set("event_type", shortcut.EventType)
+	d.Set("amount", shortcut.Amount)
+	d.Set("location_id", shortcut.Location_Id)
+	d.Set("reason_ids", shortcut.ReasonIds)
+	return nil
+}
+
+func resourceReasonShortcutUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanReasonShortcut(tx.QueryRowContext(ctx, queryGetReasonShortcutForUpdate, sql.Named("param_shortcut_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("reason shortcut %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "reason shortcut", map[string]interface{}{
+			"name":        current.Name,
+			"event_type":  current.EventType,
+			"amount":      current.Amount,
+			"location_id": current.Location_Id,
+			"reason_ids":  current.ReasonIds,
+		}); err != nil {
+			return err
+		}
+
+		// The procedure finds the shortcut by name, so a rename happens first.
+		if d.HasChange("name") {
+			_, err := tx.ExecContext(ctx, queryRenameReasonShortcut,
+				sql.Named("param_shortcut_id", id),
+				sql.Named("param_shortcut_name", d.Get("name").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return execImportReasonShortcut(ctx, tx, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceReasonShortcutRead(ctx, d, m)
+}
+
+func resourceReasonShortcutDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteReasonShortcut, sql.Named("param_shortcut_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceReasonShortcutImport accepts "Line/Unit/Shortcut".
+func resourceReasonShortcutImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Line", "Unit", "Shortcut")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetReasonShortcutIdByPath,
+		sql.Named("param_pl_desc", parts[0]),
+		sql.Named("param_pu_desc", parts[1]),
+		sql.Named("param_shortcut_name", parts[2]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reason shortcut %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
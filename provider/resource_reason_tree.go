@@ -0,0 +1,770 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// reasonTreeDepth is the number of reason levels the import procedures accept.
+const reasonTreeDepth = 4
+
+type reasonTreeNode struct {
+	id       int64
+	reasonID int
+	level    int
+	parentID int64
+}
+
+const (
+	queryGetReasonTree = `
+		SELECT ERT.Tree_Name, ERT.Group_Id
+		FROM dbo.Event_Reason_Tree AS ERT
+		WHERE ERT.Tree_Name_Id = @param_tree_id;
+		`
+
+	queryGetReasonTreeForUpdate = `
+		SELECT ERT.Tree_Name, ERT.Group_Id
+		FROM dbo.Event_Reason_Tree AS ERT WITH (UPDLOCK, HOLDLOCK)
+		WHERE ERT.Tree_Name_Id = @param_tree_id;
+		`
+
+	queryGetReasonTreeIdByName = `
+		SELECT ERT.Tree_Name_Id
+		FROM dbo.Event_Reason_Tree AS ERT
+		WHERE ERT.Tree_Name = @param_tree_name;
+		`
+
+	queryLoadReasonTreeHeaders = `
+		SELECT ERLH.Level_Name
+		FROM dbo.Event_Reason_Level_Headers AS ERLH
+		WHERE ERLH.Tree_Name_Id = @param_tree_id
+		AND ERLH.Reason_Level <= 4
+		ORDER BY ERLH.Reason_Level;
+		`
+
+	queryLoadReasonTreeNodes = `
+		SELECT ERTD.Event_Reason_Tree_Data_Id, ERTD.Event_Reason_Id, ERTD.Event_Reason_Level, ERTD.Parent_Event_R_Tree_Data_Id
+		FROM dbo.Event_Reason_Tree_Data AS ERTD
+		WHERE ERTD.Tree_Name_Id = @param_tree_id
+		ORDER BY ERTD.Event_Reason_Level, ERTD.Event_Reason_Tree_Data_Id;
+		`
+
+	// spEM_IEImportReasonTrees creates the tree on first use and sets its group and level headers.
+	queryImportReasonTree = `
+		SET XACT_ABORT ON;
+
+		DECLARE @sg_desc			NVARCHAR(50);
+
+		IF @param_group_id IS NOT NULL
+		BEGIN
+			SET @sg_desc = (
+				SELECT SG.Group_Desc FROM dbo.Security_Groups AS SG WHERE SG.Group_Id = @param_group_id);
+
+			IF @sg_desc IS NULL
+				THROW 50000, 'security group not found', 1;
+		END
+
+		EXEC	@return_value = [dbo].[spEM_IEImportReasonTrees]
+				@Tree_Name = @param_tree_name,
+				@Group_Desc = @sg_desc,
+				@Title1 = @param_title1,
+				@Title2 = @param_title2,
+				@Title3 = @param_title3,
+				@Title4 = @param_title4,
+				@Title5 = NULL,
+				@Title6 = NULL,
+				@Title7 = NULL,
+				@Title8 = NULL,
+				@Title9 = NULL,
+				@Title10 = NULL,
+				@User_Id = @param_user_id;
+		`
+
+	queryRenameReasonTree = `
+		UPDATE dbo.Event_Reason_Tree SET
+			Tree_Name = @param_tree_name
+		WHERE Tree_Name_Id = @param_tree_id;
+		`
+
+	// spEM_IEImportEventReasonTree adds the node at the end of the reason path. The path's
+	// parents already exist, so only the last level is created.
+	queryAddReasonTreeNode = `
+		SET XACT_ABORT ON;
+
+		DECLARE @tree_name			NVARCHAR(100),
+				@r1					NVARCHAR(100),
+				@r2					NVARCHAR(100),
+				@r3					NVARCHAR(100),
+				@r4					NVARCHAR(100),
+				@msg				NVARCHAR(2048);
+
+		SET @tree_name = (
+			SELECT ERT.Tree_Name FROM dbo.Event_Reason_Tree AS ERT WHERE ERT.Tree_Name_Id = @param_tree_id);
+
+		IF @tree_name IS NULL
+			THROW 50000, 'reason tree not found', 1;
+
+		SET @r1 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason1);
+		SET @r2 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason2);
+		SET @r3 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason3);
+		SET @r4 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason_id);
+
+		IF @r4 IS NULL
+		BEGIN
+			SET @msg = CONCAT('event reason ', @param_reason_id, ' not found');
+			THROW 50000, @msg, 1;
+		END
+
+		-- The leaf goes in the first level after the parents.
+		IF @param_reason1 IS NULL
+			SET @r1 = @r4;
+		ELSE IF @param_reason2 IS NULL
+			SET @r2 = @r4;
+		ELSE IF @param_reason3 IS NULL
+			SET @r3 = @r4;
+
+		IF @param_reason3 IS NULL
+			SET @r4 = NULL;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportEventReasonTree]
+				@Reason_Tree_Name = @tree_name,
+				@Reason_Level_1 = @r1,
+				@Reason_Level_2 = @r2,
+				@Reason_Level_3 = @r3,
+				@Reason_Level_4 = @r4,
+				@Add_Reasons = '0',
+				@User_Id = @param_user_id;
+
+		SET @out_node_id = (
+			SELECT ERTD.Event_Reason_Tree_Data_Id
+			FROM dbo.Event_Reason_Tree_Data AS ERTD
+			WHERE ERTD.Tree_Name_Id = @param_tree_id
+			AND ERTD.Event_Reason_Id = @param_reason_id
+			AND COALESCE(ERTD.Parent_Event_R_Tree_Data_Id, 0) = COALESCE(@param_parent_id, 0));
+		`
+
+	// reasonNodeReferenceGuard fails while anything still refers to the node at @param_path1..4
+	// (NULL past the node's level) or to one of its children. Faults and shortcuts don't record
+	// their tree, so those on units whose production events use the tree (Prod_Events.Name_Id or
+	// Action_Tree_Id) are counted.
+	reasonNodeReferenceGuard = `
+		;WITH subtree AS (
+			SELECT ERTD.Event_Reason_Tree_Data_Id
+			FROM dbo.Event_Reason_Tree_Data AS ERTD
+			WHERE ERTD.Event_Reason_Tree_Data_Id = @param_node_id
+			UNION ALL
+			SELECT ERTD.Event_Reason_Tree_Data_Id
+			FROM dbo.Event_Reason_Tree_Data AS ERTD
+			JOIN subtree AS S ON S.Event_Reason_Tree_Data_Id = ERTD.Parent_Event_R_Tree_Data_Id
+		)
+		SELECT @categories = COUNT(*)
+		FROM dbo.Event_Reason_Category_Data AS ERCD
+		JOIN subtree AS S ON S.Event_Reason_Tree_Data_Id = ERCD.Event_Reason_Tree_Data_Id;
+
+		IF @categories > 0
+			THROW 50000, 'reason node still has reason categories; remove the pa_reason_tree_category resources first', 1;
+
+		IF EXISTS (
+			SELECT 1
+			FROM dbo.Timed_Event_Fault AS TEF
+			WHERE TEF.PU_Id IN (
+				SELECT PE.PU_Id FROM dbo.Prod_Events AS PE
+				WHERE PE.Name_Id = @param_tree_id OR PE.Action_Tree_Id = @param_tree_id)
+			AND TEF.Reason_Level1 = @param_path1
+			AND (@param_path2 IS NULL OR TEF.Reason_Level2 = @param_path2)
+			AND (@param_path3 IS NULL OR TEF.Reason_Level3 = @param_path3)
+			AND (@param_path4 IS NULL OR TEF.Reason_Level4 = @param_path4))
+			THROW 50000, 'reason node is still used by a downtime fault', 1;
+
+		IF EXISTS (
+			SELECT 1
+			FROM dbo.Waste_Event_Fault AS WEF
+			WHERE WEF.PU_Id IN (
+				SELECT PE.PU_Id FROM dbo.Prod_Events AS PE
+				WHERE PE.Name_Id = @param_tree_id OR PE.Action_Tree_Id = @param_tree_id)
+			AND WEF.Reason_Level1 = @param_path1
+			AND (@param_path2 IS NULL OR WEF.Reason_Level2 = @param_path2)
+			AND (@param_path3 IS NULL OR WEF.Reason_Level3 = @param_path3)
+			AND (@param_path4 IS NULL OR WEF.Reason_Level4 = @param_path4))
+			THROW 50000, 'reason node is still used by a waste fault', 1;
+
+		IF EXISTS (
+			SELECT 1
+			FROM dbo.Reason_Shortcuts AS RS
+			WHERE RS.PU_Id IN (
+				SELECT PE.PU_Id FROM dbo.Prod_Events AS PE
+				WHERE PE.Name_Id = @param_tree_id OR PE.Action_Tree_Id = @param_tree_id)
+			AND RS.Reason_Level1 = @param_path1
+			AND (@param_path2 IS NULL OR RS.Reason_Level2 = @param_path2)
+			AND (@param_path3 IS NULL OR RS.Reason_Level3 = @param_path3)
+			AND (@param_path4 IS NULL OR RS.Reason_Level4 = @param_path4))
+			THROW 50000, 'reason node is still used by a reason shortcut', 1;
+
+		IF EXISTS (
+			SELECT 1
+			FROM dbo.Alarm_Templates AS AT
+			WHERE (AT.Cause_Tree_Id = @param_tree_id
+				AND AT.Default_Cause1 = @param_path1
+				AND (@param_path2 IS NULL OR AT.Default_Cause2 = @param_path2)
+				AND (@param_path3 IS NULL OR AT.Default_Cause3 = @param_path3)
+				AND (@param_path4 IS NULL OR AT.Default_Cause4 = @param_path4))
+			OR (AT.Action_Tree_Id = @param_tree_id
+				AND AT.Default_Action1 = @param_path1
+				AND (@param_path2 IS NULL OR AT.Default_Action2 = @param_path2)
+				AND (@param_path3 IS NULL OR AT.Default_Action3 = @param_path3)
+				AND (@param_path4 IS NULL OR AT.Default_Action4 = @param_path4)))
+			THROW 50000, 'reason node is still a default cause or action of an alarm template', 1;
+		`
+
+	// PA has no procedure that removes a single node, so this deletes the row directly once
+	// reasonNodeReferenceGuard has found nothing left pointing at the node's path.
+	queryRemoveReasonTreeNode = `
+		SET XACT_ABORT ON;
+
+		DECLARE @categories INT;
+		` + reasonNodeReferenceGuard + `
+		DELETE FROM dbo.Event_Reason_Tree_Data
+		WHERE Event_Reason_Tree_Data_Id = @param_node_id;
+		`
+
+	// Faults and shortcuts sit on units, so once no unit uses the tree none point into it.
+	queryDeleteReasonTree = `
+		SET XACT_ABORT ON;
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Prod_Events AS PE
+			WHERE PE.Name_Id = @param_tree_id OR PE.Action_Tree_Id = @param_tree_id)
+			THROW 50000, 'reason tree is still used by a unit', 1;
+
+		IF EXISTS (
+			SELECT 1 FROM dbo.Alarm_Templates AS AT
+			WHERE AT.Cause_Tree_Id = @param_tree_id OR AT.Action_Tree_Id = @param_tree_id)
+			THROW 50000, 'reason tree is still used by an alarm template', 1;
+
+		IF EXISTS (
+			SELECT 1
+			FROM dbo.Event_Reason_Category_Data AS ERCD
+			JOIN dbo.Event_Reason_Tree_Data AS ERTD ON ERTD.Event_Reason_Tree_Data_Id = ERCD.Event_Reason_Tree_Data_Id
+			WHERE ERTD.Tree_Name_Id = @param_tree_id)
+			THROW 50000, 'reason tree still has reason categories; remove the pa_reason_tree_category resources first', 1;
+
+		DELETE FROM dbo.Event_Reason_Tree_Data WHERE Tree_Name_Id = @param_tree_id;
+		DELETE FROM dbo.Event_Reason_Level_Headers WHERE Tree_Name_Id = @param_tree_id;
+		DELETE FROM dbo.Event_Reason_Tree WHERE Tree_Name_Id = @param_tree_id;
+		`
+)
+
+// reasonTreeNodeSchema nests node blocks down to reasonTreeDepth levels.
+func reasonTreeNodeSchema(level int) *schema.Schema {
+	elem := map[string]*schema.Schema{
+		"reason_id": {
+			Type:     schema.TypeInt,
+			Required: true,
+		},
+	}
+	if level < reasonTreeDepth {
+		elem["node"] = reasonTreeNodeSchema(level + 1)
+	}
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Optional:    true,
+		Description: fmt.Sprintf("Level %d reasons.", level),
+		Elem:        &schema.Resource{Schema: elem},
+	}
+}
+
+func resourceReasonTree() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceReasonTreeCreate,
+		ReadContext:   resourceReasonTreeRead,
+		UpdateContext: resourceReasonTreeUpdate,
+		DeleteContext: resourceReasonTreeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceReasonTreeImport,
+		},
+		CustomizeDiff: resourceReasonTreeCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"tree_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Tree_Name.",
+				ValidateFunc: validation.StringLenBetween(1, 50),
+			},
+			"security_group_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"level_names": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    reasonTreeDepth,
+				Description: "Level header names, from level 1 down.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringLenBetween(1, 50),
+				},
+			},
+			"node": reasonTreeNodeSchema(1),
+		},
+	}
+}
+
+// flattenReasonTreePaths returns the reason path of every configured node, parents first.
+func flattenReasonTreePaths(nodes []interface{}, prefix []int) [][]int {
+	var paths [][]int
+	for _, v := range nodes {
+		block := v.(map[string]interface{})
+		path := append(append([]int{}, prefix...), block["reason_id"].(int))
+		paths = append(paths, path)
+		if children, ok := block["node"].(*schema.Set); ok {
+			paths = append(paths, flattenReasonTreePaths(children.List(), path)...)
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool { return len(paths[i]) < len(paths[j]) })
+	return paths
+}
+
+func reasonTreePathKey(path []int) string {
+	parts := make([]string, len(path))
+	for i, reasonID := range path {
+		parts[i] = fmt.Sprint(reasonID)
+	}
+	return strings.Join(parts, "/")
+}
+
+func resourceReasonTreeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	seen := map[string]bool{}
+	for _, path := range flattenReasonTreePaths(d.Get("node").(*schema.Set).List(), nil) {
+		key := reasonTreePathKey(path)
+		if path[len(path)-1] != 0 && seen[key] {
+			return fmt.Errorf("reason %d appears more than once under the same parent (%s)", path[len(path)-1], key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+func loadReasonTreeNodes(rows *sql.Rows, err error) (map[int64]*reasonTreeNode, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := map[int64]*reasonTreeNode{}
+	for rows.Next() {
+		var node reasonTreeNode
+		var reasonID, level int64
+		var parentID sql.NullInt64
+		if err := rows.Scan(&node.id, &reasonID, &level, &parentID); err != nil {
+			return nil, err
+		}
+		node.reasonID = int(reasonID)
+		node.level = int(level)
+		node.parentID = nullableIdToInt64(parentID)
+		nodes[node.id] = &node
+	}
+	return nodes, rows.Err()
+}
+
+func reasonTreeNodePath(nodes map[int64]*reasonTreeNode, node *reasonTreeNode) []int {
+	var path []int
+	for n := node; n != nil; n = nodes[n.parentID] {
+		path = append([]int{n.reasonID}, path...)
+	}
+	return path
+}
+
+func sortedReasonTreeNodeIds(nodes map[int64]*reasonTreeNode) []int64 {
+	ids := make([]int64, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// reasonTreeOp is one step of a tree sync. Nodes that don't exist yet carry negative
+// placeholder IDs, which later steps may use as their parent.
+type reasonTreeOp struct {
+	kind     string // "add" or "remove"
+	nodeID   int64
+	parentID int64
+	path     []int
+}
+
+// planReasonTreeSync works out how to make nodes match desired. A node already at its path is
+// kept. Everything else is added through spEM_IEImportEventReasonTree, which fills in the
+// node's level and ancestor columns, and nodes left over are removed deepest first. A reason
+// that moves to another parent is therefore added at its new path and removed from its old
+// one, along with the rest of its subtree.
+func planReasonTreeSync(current map[int64]*reasonTreeNode, desired [][]int) []reasonTreeOp {
+	nodes := map[int64]*reasonTreeNode{}
+	for id, node := range current {
+		copied := *node
+		nodes[id] = &copied
+	}
+
+	var ops []reasonTreeOp
+	placed := map[string]int64{}
+	used := map[int64]bool{}
+	nextPlaceholder := int64(-1)
+	for _, path := range desired {
+		key := reasonTreePathKey(path)
+		level := len(path)
+		reasonID := path[level-1]
+		var parentID int64
+		if level > 1 {
+			parentID = placed[reasonTreePathKey(path[:level-1])]
+		}
+
+		var kept int64
+		for _, id := range sortedReasonTreeNodeIds(nodes) {
+			node := nodes[id]
+			if id > 0 && !used[id] && node.reasonID == reasonID && node.level == level && node.parentID == parentID {
+				kept = id
+				break
+			}
+		}
+
+		if kept != 0 {
+			placed[key] = kept
+		} else {
+			id := nextPlaceholder
+			nextPlaceholder--
+			ops = append(ops, reasonTreeOp{kind: "add", nodeID: id, parentID: parentID, path: path})
+			nodes[id] = &reasonTreeNode{id: id, reasonID: reasonID, level: level, parentID: parentID}
+			placed[key] = id
+		}
+		used[placed[key]] = true
+	}
+
+	var leftover []*reasonTreeNode
+	for _, id := range sortedReasonTreeNodeIds(nodes) {
+		if !used[id] {
+			leftover = append(leftover, nodes[id])
+		}
+	}
+	sort.SliceStable(leftover, func(i, j int) bool { return leftover[i].level > leftover[j].level })
+	for _, node := range leftover {
+		ops = append(ops, reasonTreeOp{kind: "remove", nodeID: node.id, parentID: node.parentID, path: reasonTreeNodePath(nodes, node)})
+	}
+	return ops
+}
+
+// reasonNodePathArgs passes path as @param_path1..4.
+func reasonNodePathArgs(path []int) []interface{} {
+	reasonIDs := make([]interface{}, len(path))
+	for i, reasonID := range path {
+		reasonIDs[i] = reasonID
+	}
+	return reasonPathArgs("param_path", reasonIDs)
+}
+
+// syncReasonTree applies planReasonTreeSync to the tree. Removals fail while faults, shortcuts,
+// alarm templates or categories still refer to the node's path.
+func syncReasonTree(ctx context.Context, tx *sql.Tx, treeID int64, desired [][]int) error {
+	userId := 1
+
+	nodes, err := loadReasonTreeNodes(tx.QueryContext(ctx, queryLoadReasonTreeNodes, sql.Named("param_tree_id", treeID)))
+	if err != nil {
+		return err
+	}
+
+	added := map[int64]int64{}
+	resolve := func(id int64) int64 {
+		if id < 0 {
+			return added[id]
+		}
+		return id
+	}
+
+	for _, op := range planReasonTreeSync(nodes, desired) {
+		key := reasonTreePathKey(op.path)
+		parentID := resolve(op.parentID)
+		switch op.kind {
+		case "remove":
+			args := append([]interface{}{
+				sql.Named("param_tree_id", treeID),
+				sql.Named("param_node_id", op.nodeID),
+			}, reasonNodePathArgs(op.path)...)
+			if _, err := tx.ExecContext(ctx, queryRemoveReasonTreeNode, args...); err != nil {
+				return fmt.Errorf("failed to remove reason node %s: %w", key, err)
+			}
+		case "add":
+			level := len(op.path)
+			var returnValue, nodeID sql.NullInt64
+			ancestors := make([]interface{}, reasonTreeDepth-1)
+			for i := range ancestors {
+				ancestors[i] = sql.NullInt64{}
+				if i < level-1 {
+					ancestors[i] = int64(op.path[i])
+				}
+			}
+			_, err := tx.ExecContext(ctx, queryAddReasonTreeNode,
+				sql.Named("return_value", sql.Out{Dest: &returnValue}),
+				sql.Named("param_tree_id", treeID),
+				sql.Named("param_reason1", ancestors[0]),
+				sql.Named("param_reason2", ancestors[1]),
+				sql.Named("param_reason3", ancestors[2]),
+				sql.Named("param_reason_id", int64(op.path[level-1])),
+				sql.Named("param_parent_id", idToNullInt64(parentID)),
+				sql.Named("param_user_id", userId),
+				sql.Named("out_node_id", sql.Out{Dest: &nodeID}),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to add reason node %s: %w", key, err)
+			}
+			if returnValue.Int64 != 0 || !nodeID.Valid {
+				return fmt.Errorf("stored procedure returned failure status %d adding reason node %s", returnValue.Int64, key)
+			}
+			added[op.nodeID] = nodeID.Int64
+		}
+	}
+	return nil
+}
+
+// buildReasonTreeBlocks renders the children of parentID as node blocks.
+func buildReasonTreeBlocks(nodes map[int64]*reasonTreeNode, parentID int64) []interface{} {
+	blocks := []interface{}{}
+	for _, id := range sortedReasonTreeNodeIds(nodes) {
+		node := nodes[id]
+		if node.parentID != parentID || node.level > reasonTreeDepth {
+			continue
+		}
+		block := map[string]interface{}{"reason_id": node.reasonID}
+		if node.level < reasonTreeDepth {
+			block["node"] = buildReasonTreeBlocks(nodes, node.id)
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func execImportReasonTree(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) error {
+	userId := 1
+
+	titles := make([]sql.NullString, reasonTreeDepth)
+	for i, v := range d.Get("level_names").([]interface{}) {
+		titles[i] = stringToNullString(v.(string))
+	}
+
+	var returnValue sql.NullInt64
+	_, err := tx.ExecContext(ctx, queryImportReasonTree,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_tree_name", d.Get("name").(string)),
+		sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+		sql.Named("param_title1", titles[0]),
+		sql.Named("param_title2", titles[1]),
+		sql.Named("param_title3", titles[2]),
+		sql.Named("param_title4", titles[3]),
+		sql.Named("param_user_id", userId),
+	)
+	if err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceReasonTreeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	var treeID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetReasonTreeIdByName, sql.Named("param_tree_name", name)).Scan(&treeID)
+		if err == nil {
+			return fmt.Errorf("reason tree %q already exists; import it instead", name)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportReasonTree(ctx, tx, d); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetReasonTreeIdByName, sql.Named("param_tree_name", name)).Scan(&treeID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("reason tree %q was not created", name)
+		}
+		if err != nil {
+			return err
+		}
+
+		return syncReasonTree(ctx, tx, treeID, flattenReasonTreePaths(d.Get("node").(*schema.Set).List(), nil))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("tree_id", int(treeID))
+	d.SetId(int64ToString(treeID))
+	return resourceReasonTreeRead(ctx, d, m)
+}
+
+func resourceReasonTreeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var name sql.NullString
+	var groupID sql.NullInt64
+	err = db.QueryRowContext(ctx, queryGetReasonTree, sql.Named("param_tree_id", id)).Scan(&name, &groupID)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	levelNames, err := loadReasonTreeHeaders(db.QueryContext(ctx, queryLoadReasonTreeHeaders, sql.Named("param_tree_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	nodes, err := loadReasonTreeNodes(db.QueryContext(ctx, queryLoadReasonTreeNodes, sql.Named("param_tree_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("tree_id", id)
+	d.Set("name", nullableStringToString(name))
+	d.Set("security_group_id", nullableIdToInt64(groupID))
+	d.Set("level_names", levelNames)
+	if err := d.Set("node", buildReasonTreeBlocks(nodes, 0)); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func loadReasonTreeHeaders(rows *sql.Rows, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []interface{}
+	for rows.Next() {
+		var name sql.NullString
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, nullableStringToString(name))
+	}
+	return names, rows.Err()
+}
+
+func resourceReasonTreeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		if d.HasChanges("name", "security_group_id", "level_names") {
+			var name sql.NullString
+			var groupID sql.NullInt64
+			err := tx.QueryRowContext(ctx, queryGetReasonTreeForUpdate, sql.Named("param_tree_id", id)).Scan(&name, &groupID)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("reason tree %d no longer exists", id)
+			}
+			if err != nil {
+				return err
+			}
+			levelNames, err := loadReasonTreeHeaders(tx.QueryContext(ctx, queryLoadReasonTreeHeaders, sql.Named("param_tree_id", id)))
+			if err != nil {
+				return err
+			}
+			if err := checkUnchanged(d, "reason tree", map[string]interface{}{
+				"name":              nullableStringToString(name),
+				"security_group_id": nullableIdToInt64(groupID),
+				"level_names":       levelNames,
+			}); err != nil {
+				return err
+			}
+
+			if d.HasChange("name") {
+				_, err := tx.ExecContext(ctx, queryRenameReasonTree,
+					sql.Named("param_tree_id", id),
+					sql.Named("param_tree_name", d.Get("name").(string)),
+				)
+				if err != nil {
+					return err
+				}
+			}
+			if err := execImportReasonTree(ctx, tx, d); err != nil {
+				return err
+			}
+		}
+		return syncReasonTree(ctx, tx, id, flattenReasonTreePaths(d.Get("node").(*schema.Set).List(), nil))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceReasonTreeRead(ctx, d, m)
+}
+
+func resourceReasonTreeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteReasonTree, sql.Named("param_tree_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceReasonTreeImport accepts the tree name.
+func resourceReasonTreeImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetReasonTreeIdByName,
+		sql.Named("param_tree_name", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reason tree %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
+
+// findReasonTreeNode returns the node at the reason path, or nil.
+func findReasonTreeNode(nodes map[int64]*reasonTreeNode, path []int) *reasonTreeNode {
+	key := reasonTreePathKey(path)
+	for _, id := range sortedReasonTreeNodeIds(nodes) {
+		if node := nodes[id]; node.level == len(path) && reasonTreePathKey(reasonTreeNodePath(nodes, node)) == key {
+			return node
+		}
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetReasonTreeNodeCategory = `
+		SELECT ERCD.ERC_Id
+		FROM dbo.Event_Reason_Category_Data AS ERCD
+		WHERE ERCD.Event_Reason_Tree_Data_Id = @param_node_id
+		AND ERCD.ERC_Id = @param_category_id;
+		`
+
+	// spEM_IEImportReasonCategory attaches the category to the node at the end of the reason path;
+	// PA propagates it to the node's children.
+	queryAddReasonTreeNodeCategory = `
+		SET XACT_ABORT ON;
+
+		DECLARE @tree_name			NVARCHAR(100),
+				@category_desc		NVARCHAR(100),
+				@r1					NVARCHAR(100),
+				@r2					NVARCHAR(100),
+				@r3					NVARCHAR(100),
+				@r4					NVARCHAR(100);
+
+		SET @tree_name = (
+			SELECT ERT.Tree_Name FROM dbo.Event_Reason_Tree AS ERT WHERE ERT.Tree_Name_Id = @param_tree_id);
+
+		SET @category_desc = (
+			SELECT ERC.ERC_Desc FROM dbo.Event_Reason_Catagories AS ERC WHERE ERC.ERC_Id = @param_category_id);
+
+		IF @category_desc IS NULL
+			THROW 50000, 'reason category not found', 1;
+
+		SET @r1 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason1);
+		SET @r2 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason2);
+		SET @r3 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason3);
+		SET @r4 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason4);
+
+		EXEC	@return_value = [dbo].[spEM_IEImportReasonCategory]
+				@TreeName = @tree_name,
+				@Level1 = @r1,
+				@Level2 = @r2,
+				@Level3 = @r3,
+				@Level4 = @r4,
+				@Category = @category_desc,
+				@User_Id = @param_user_id;
+		`
+
+	// Removing the category from a node also removes the copies propagated from it.
+	queryRemoveReasonTreeNodeCategory = `
+		DELETE FROM dbo.Event_Reason_Category_Data
+		WHERE ERC_Id = @param_category_id
+		AND (Event_Reason_Tree_Data_Id = @param_node_id OR Propegated_From_ETDId = @param_node_id);
+		`
+)
+
+// resourceReasonTreeCategory attaches one reason category to one reason tree node.
+func resourceReasonTreeCategory() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceReasonTreeCategoryCreate,
+		ReadContext:   resourceReasonTreeCategoryRead,
+		DeleteContext: resourceReasonTreeCategoryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceReasonTreeCategoryImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"tree_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"reason_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				MaxItems:    reasonTreeDepth,
+				Description: "Reason path of the node, from level 1 down.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+			"category_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func reasonPathFromList(values []interface{}) []int {
+	path := make([]int, len(values))
+	for i, v := range values {
+		path[i] = v.(int)
+	}
+	return path
+}
+
+// parseReasonTreeCategoryId splits the "tree/category/reason1[/reason2...]" ID.
+func parseReasonTreeCategoryId(id string) (treeID, categoryID int64, path []int, err error) {
+	parts := strings.Split(id, "/")
+	if len(parts) < 3 || len(parts) > 2+reasonTreeDepth {
+		return 0, 0, nil, fmt.Errorf("unexpected reason tree category ID %q", id)
+	}
+	if treeID, err = stringToInt64(parts[0]); err != nil {
+		return
+	}
+	if categoryID, err = stringToInt64(parts[1]); err != nil {
+		return
+	}
+	for _, part := range parts[2:] {
+		var reasonID int64
+		if reasonID, err = stringToInt64(part); err != nil {
+			return
+		}
+		path = append(path, int(reasonID))
+	}
+	return
+}
+
+func resourceReasonTreeCategoryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+	treeID := int64(d.Get("tree_id").(int))
+	categoryID := int64(d.Get("category_id").(int))
+	path := reasonPathFromList(d.Get("reason_ids").([]interface{}))
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		nodes, err := loadReasonTreeNodes(tx.QueryContext(ctx, queryLoadReasonTreeNodes, sql.Named("param_tree_id", treeID)))
+		if err != nil {
+			return err
+		}
+		node := findReasonTreeNode(nodes, path)
+		if node == nil {
+			return fmt.Errorf("reason tree %d has no node %s", treeID, reasonTreePathKey(path))
+		}
+
+		var existing int64
+		err = tx.QueryRowContext(ctx, queryGetReasonTreeNodeCategory,
+			sql.Named("param_node_id", node.id),
+			sql.Named("param_category_id", categoryID),
+		).Scan(&existing)
+		if err == nil {
+			return fmt.Errorf("category %d is already attached to node %s; import it instead", categoryID, reasonTreePathKey(path))
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		levels := make([]interface{}, reasonTreeDepth)
+		for i := range levels {
+			levels[i] = sql.NullInt64{}
+			if i < len(path) {
+				levels[i] = int64(path[i])
+			}
+		}
+
+		var returnValue sql.NullInt64
+		_, err = tx.ExecContext(ctx, queryAddReasonTreeNodeCategory,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_tree_id", treeID),
+			sql.Named("param_category_id", categoryID),
+			sql.Named("param_reason1", levels[0]),
+			sql.Named("param_reason2", levels[1]),
+			sql.Named("param_reason3", levels[2]),
+			sql.Named("param_reason4", levels[3]),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d/%s", treeID, categoryID, reasonTreePathKey(path)))
+	return resourceReasonTreeCategoryRead(ctx, d, m)
+}
+
+func resourceReasonTreeCategoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	treeID, categoryID, path, err := parseReasonTreeCategoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	nodes, err := loadReasonTreeNodes(db.QueryContext(ctx, queryLoadReasonTreeNodes, sql.Named("param_tree_id", treeID)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	node := findReasonTreeNode(nodes, path)
+	if node == nil {
+		d.SetId("")
+		return nil
+	}
+
+	var existing int64
+	err = db.QueryRowContext(ctx, queryGetReasonTreeNodeCategory,
+		sql.Named("param_node_id", node.id),
+		sql.Named("param_category_id", categoryID),
+	).Scan(&existing)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	reasonIDs := make([]interface{}, len(path))
+	for i, reasonID := range path {
+		reasonIDs[i] = reasonID
+	}
+
+	d.Set("tree_id", treeID)
+	d.Set("category_id", categoryID)
+	d.Set("reason_ids", reasonIDs)
+	return nil
+}
+
+func resourceReasonTreeCategoryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	treeID, categoryID, path, err := parseReasonTreeCategoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		nodes, err := loadReasonTreeNodes(tx.QueryContext(ctx, queryLoadReasonTreeNodes, sql.Named("param_tree_id", treeID)))
+		if err != nil {
+			return err
+		}
+		node := findReasonTreeNode(nodes, path)
+		if node == nil {
+			return nil
+		}
+		_, err = tx.ExecContext(ctx, queryRemoveReasonTreeNodeCategory,
+			sql.Named("param_node_id", node.id),
+			sql.Named("param_category_id", categoryID),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceReasonTreeCategoryImport accepts "Tree/Category/Reason1[/Reason2...]".
+func resourceReasonTreeCategoryImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	db := getDB(m)
+
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) < 3 || len(parts) > 2+reasonTreeDepth {
+		return nil, fmt.Errorf("unexpected import ID %q, expected Tree/Category/Reason1[/Reason2...]", d.Id())
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("unexpected import ID %q, names must not be empty", d.Id())
+		}
+	}
+
+	var treeID, categoryID int64
+	err := db.QueryRowContext(ctx, queryGetReasonTreeIdByName, sql.Named("param_tree_name", parts[0])).Scan(&treeID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reason tree %q not found", parts[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = db.QueryRowContext(ctx, queryGetReasonCategoryIdByDesc, sql.Named("param_category_desc", parts[1])).Scan(&categoryID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("reason category %q not found", parts[1])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var path []int
+	for _, name := range parts[2:] {
+		var reasonID int64
+		err := db.QueryRowContext(ctx, queryGetEventReasonIdByName, sql.Named("param_reason_name", name)).Scan(&reasonID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("event reason %q not found", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, int(reasonID))
+	}
+
+	d.SetId(fmt.Sprintf("%d/%d/%s", treeID, categoryID, reasonTreePathKey(path)))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReasonTreeCategoryId(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           string
+		wantTree     int64
+		wantCategory int64
+		wantPath     []int
+		wantErr      bool
+	}{
+		{name: "level 1 node", id: "3/7/11", wantTree: 3, wantCategory: 7, wantPath: []int{11}},
+		{name: "level 4 node", id: "3/7/11/12/13/14", wantTree: 3, wantCategory: 7, wantPath: []int{11, 12, 13, 14}},
+		{name: "no reason path", id: "3/7", wantErr: true},
+		{name: "path too deep", id: "3/7/11/12/13/14/15", wantErr: true},
+		{name: "tree not numeric", id: "Downtime/7/11", wantErr: true},
+		{name: "category not numeric", id: "3/Mechanical/11", wantErr: true},
+		{name: "reason not numeric", id: "3/7/11/Jam", wantErr: true},
+		{name: "empty reason", id: "3/7/11//13", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			treeID, categoryID, path, err := parseReasonTreeCategoryId(tt.id)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %d/%d/%v", treeID, categoryID, path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if treeID != tt.wantTree || categoryID != tt.wantCategory || !reflect.DeepEqual(path, tt.wantPath) {
+				t.Errorf("got %d/%d/%v, want %d/%d/%v", treeID, categoryID, path, tt.wantTree, tt.wantCategory, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestFlattenReasonTreePaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []interface{}
+		want  [][]int
+	}{
+		{
+			name: "one level",
+			nodes: []interface{}{
+				map[string]interface{}{"reason_id": 2},
+				map[string]interface{}{"reason_id": 1},
+			},
+			want: [][]int{{1}, {2}},
+		},
+		{
+			name: "four levels",
+			nodes: []interface{}{
+				map[string]interface{}{
+					"reason_id": 1,
+					"node": []interface{}{
+						map[string]interface{}{
+							"reason_id": 2,
+							"node": []interface{}{
+								map[string]interface{}{
+									"reason_id": 3,
+									"node": []interface{}{
+										map[string]interface{}{"reason_id": 4},
+									},
+								},
+							},
+						},
+						map[string]interface{}{"reason_id": 5},
+					},
+				},
+				map[string]interface{}{"reason_id": 6},
+			},
+			want: [][]int{{1}, {6}, {1, 2}, {1, 5}, {1, 2, 3}, {1, 2, 3, 4}},
+		},
+		{
+			name: "same reason under different parents",
+			nodes: []interface{}{
+				map[string]interface{}{
+					"reason_id": 1,
+					"node":      []interface{}{map[string]interface{}{"reason_id": 9}},
+				},
+				map[string]interface{}{
+					"reason_id": 2,
+					"node":      []interface{}{map[string]interface{}{"reason_id": 9}},
+				},
+			},
+			want: [][]int{{1}, {2}, {1, 9}, {2, 9}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, resourceReasonTree().Schema, map[string]interface{}{
+				"name": "Downtime Reasons",
+				"node": tt.nodes,
+			})
+			got := flattenReasonTreePaths(d.Get("node").(*schema.Set).List(), nil)
+
+			// Set order is not stable, but every parent must come before its children.
+			seen := map[string]bool{}
+			for _, path := range got {
+				if len(path) > 1 && !seen[reasonTreePathKey(path[:len(path)-1])] {
+					t.Errorf("%v comes before its parent in %v", path, got)
+				}
+				seen[reasonTreePathKey(path)] = true
+			}
+
+			sortReasonPaths(got)
+			sortReasonPaths(tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func sortReasonPaths(paths [][]int) {
+	sort.Slice(paths, func(i, j int) bool { return reasonTreePathKey(paths[i]) < reasonTreePathKey(paths[j]) })
+}
+
+func TestPlanReasonTreeSync(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodes   []reasonTreeNode
+		desired [][]int
+		want    []reasonTreeOp
+	}{
+		{
+			name:    "empty tree",
+			desired: [][]int{{1}, {1, 2}},
+			want: []reasonTreeOp{
+				{kind: "add", nodeID: -1, parentID: 0, path: []int{1}},
+				{kind: "add", nodeID: -2, parentID: -1, path: []int{1, 2}},
+			},
+		},
+		{
+			name: "unchanged",
+			nodes: []reasonTreeNode{
+				{id: 10, reasonID: 1, level: 1},
+				{id: 12, reasonID: 3, level: 2, parentID: 10},
+			},
+			desired: [][]int{{1}, {1, 3}},
+		},
+		{
+			name: "reason moves to another parent",
+			nodes: []reasonTreeNode{
+				{id: 10, reasonID: 1, level: 1},
+				{id: 11, reasonID: 2, level: 1},
+				{id: 12, reasonID: 3, level: 2, parentID: 10},
+			},
+			desired: [][]int{{1}, {2}, {2, 3}},
+			want: []reasonTreeOp{
+				{kind: "add", nodeID: -1, parentID: 11, path: []int{2, 3}},
+				{kind: "remove", nodeID: 12, parentID: 10, path: []int{1, 3}},
+			},
+		},
+		{
+			name: "reason still wanted at its old place is only added",
+			nodes: []reasonTreeNode{
+				{id: 10, reasonID: 1, level: 1},
+				{id: 11, reasonID: 2, level: 1},
+				{id: 12, reasonID: 3, level: 2, parentID: 10},
+			},
+			desired: [][]int{{1}, {2}, {1, 3}, {2, 3}},
+			want: []reasonTreeOp{
+				{kind: "add", nodeID: -1, parentID: 11, path: []int{2, 3}},
+			},
+		},
+		{
+			name: "reason changing level is added and removed",
+			nodes: []reasonTreeNode{
+				{id: 10, reasonID: 1, level: 1},
+				{id: 12, reasonID: 3, level: 2, parentID: 10},
+			},
+			desired: [][]int{{1}, {3}},
+			want: []reasonTreeOp{
+				{kind: "add", nodeID: -1, parentID: 0, path: []int{3}},
+				{kind: "remove", nodeID: 12, parentID: 10, path: []int{1, 3}},
+			},
+		},
+		{
+			name: "moved under a new parent",
+			nodes: []reasonTreeNode{
+				{id: 10, reasonID: 1, level: 1},
+				{id: 12, reasonID: 3, level: 2, parentID: 10},
+			},
+			desired: [][]int{{1}, {2}, {2, 3}},
+			want: []reasonTreeOp{
+				{kind: "add", nodeID: -1, parentID: 0, path: []int{2}},
+				{kind: "add", nodeID: -2, parentID: -1, path: []int{2, 3}},
+				{kind: "remove", nodeID: 12, parentID: 10, path: []int{1, 3}},
+			},
+		},
+		{
+			name: "removed deepest first",
+			nodes: []reasonTreeNode{
+				{id: 10, reasonID: 1, level: 1},
+				{id: 11, reasonID: 2, level: 1},
+				{id: 12, reasonID: 3, level: 2, parentID: 10},
+				{id: 13, reasonID: 4, level: 3, parentID: 12},
+			},
+			desired: [][]int{{2}},
+			want: []reasonTreeOp{
+				{kind: "remove", nodeID: 13, parentID: 12, path: []int{1, 3, 4}},
+				{kind: "remove", nodeID: 12, parentID: 10, path: []int{1, 3}},
+				{kind: "remove", nodeID: 10, parentID: 0, path: []int{1}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes := map[int64]*reasonTreeNode{}
+			for i := range tt.nodes {
+				nodes[tt.nodes[i].id] = &tt.nodes[i]
+			}
+			before := map[int64]reasonTreeNode{}
+			for id, node := range nodes {
+				before[id] = *node
+			}
+
+			got := planReasonTreeSync(nodes, tt.desired)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			for id, node := range nodes {
+				if *node != before[id] {
+					t.Errorf("node %d was changed to %+v", id, *node)
+				}
+			}
+		})
+	}
+}
+
+// applyReasonTreeOps plays ops against nodes the way syncReasonTree does against the database.
+func applyReasonTreeOps(t *testing.T, nodes map[int64]*reasonTreeNode, ops []reasonTreeOp) {
+	added := map[int64]int64{}
+	nextID := int64(100)
+	for _, op := range ops {
+		parentID := op.parentID
+		if parentID < 0 {
+			parentID = added[parentID]
+		}
+		switch op.kind {
+		case "add":
+			if parentID == 0 && len(op.path) > 1 {
+				t.Fatalf("%v is added before its parent", op.path)
+			}
+			nodes[nextID] = &reasonTreeNode{id: nextID, reasonID: op.path[len(op.path)-1], level: len(op.path), parentID: parentID}
+			added[op.nodeID] = nextID
+			nextID++
+		case "remove":
+			for _, node := range nodes {
+				if node.parentID == op.nodeID {
+					t.Fatalf("%v is removed while it still has children", op.path)
+				}
+			}
+			if got := reasonTreeNodePath(nodes, nodes[op.nodeID]); !reflect.DeepEqual(got, op.path) {
+				t.Fatalf("node %d is at %v, not %v", op.nodeID, got, op.path)
+			}
+			delete(nodes, op.nodeID)
+		default:
+			t.Fatalf("unexpected op %q", op.kind)
+		}
+	}
+}
+
+func TestSyncReasonTreeMovesSubtree(t *testing.T) {
+	nodes := map[int64]*reasonTreeNode{
+		10: {id: 10, reasonID: 1, level: 1},
+		11: {id: 11, reasonID: 2, level: 1},
+		12: {id: 12, reasonID: 3, level: 2, parentID: 10},
+		13: {id: 13, reasonID: 4, level: 3, parentID: 12},
+		14: {id: 14, reasonID: 5, level: 4, parentID: 13},
+		15: {id: 15, reasonID: 6, level: 3, parentID: 12},
+	}
+	desired := [][]int{{1}, {2}, {2, 3}, {2, 3, 4}, {2, 3, 6}, {2, 3, 4, 5}}
+
+	applyReasonTreeOps(t, nodes, planReasonTreeSync(nodes, desired))
+
+	var got [][]int
+	for _, node := range nodes {
+		if node.level != len(reasonTreeNodePath(nodes, node)) {
+			t.Errorf("node %d has level %d at %v", node.id, node.level, reasonTreeNodePath(nodes, node))
+		}
+		got = append(got, reasonTreeNodePath(nodes, node))
+	}
+	sortReasonPaths(got)
+	sortReasonPaths(desired)
+	if !reflect.DeepEqual(got, desired) {
+		t.Errorf("got %v, want %v", got, desired)
+	}
+	for _, id := range []int64{12, 13, 14, 15} {
+		if nodes[id] != nil {
+			t.Errorf("node %d under the old parent was kept", id)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type SecurityGroup struct {
+	Group_Id     int64
+	Description  string
+	ExternalLink string
+	ExtendedInfo string
+}
+
+const (
+	queryGetSecurityGroup = `
+		SELECT SG.Group_Id, SG.Group_Desc, SG.External_Link, SG.Extended_Info
+		FROM dbo.Security_Groups AS SG
+		WHERE SG.Group_Id = @param_group_id;
+		`
+
+	queryGetSecurityGroupForUpdate = `
+		SELECT SG.Group_Id, SG.Group_Desc, SG.External_Link, SG.Extended_Info
+		FROM dbo.Security_Groups AS SG WITH (UPDLOCK, HOLDLOCK)
+		WHERE SG.Group_Id = @param_group_id;
+		`
+
+	queryGetSecurityGroupIdByDesc = `
+		SELECT SG.Group_Id
+		FROM dbo.Security_Groups AS SG
+		WHERE SG.Group_Desc = @param_group_desc;
+		`
+
+	queryCreateSecurityGroup = `
+		SET XACT_ABORT ON;
+
+		IF EXISTS (SELECT 1 FROM dbo.Security_Groups AS SG WHERE SG.Group_Desc = @param_group_desc)
+			THROW 50000, 'a security group with this description already exists; import it instead', 1;
+
+		EXEC	@return_value = [dbo].[spEM_CreateSecurityGroup]
+				@Group_Desc = @param_group_desc,
+				@User_Id = @param_user_id,
+				@Group_Id = @out_group_id OUTPUT;
+		`
+
+	queryUpdateSecurityGroup = `
+		UPDATE dbo.Security_Groups SET
+			Group_Desc = @param_group_desc,
+			External_Link = @param_ext_link,
+			Extended_Info = @param_ext_info
+		WHERE Group_Id = @param_group_id;
+		`
+
+	queryDeleteSecurityGroup = `
+		EXEC	@return_value = [dbo].[spEM_DropSecurityGroup]
+				@Group_Id = @param_group_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceSecurityGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSecurityGroupCreate,
+		ReadContext:   resourceSecurityGroupRead,
+		UpdateContext: resourceSecurityGroupUpdate,
+		DeleteContext: resourceSecurityGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSecurityGroupImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Group_Desc.",
+				ValidateFunc: validateTitle(),
+			},
+			"external_link": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"extended_info": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+		},
+	}
+}
+
+func scanSecurityGroup(row interface{ Scan(...interface{}) error }) (*SecurityGroup, error) {
+	var group SecurityGroup
+	var description, externalLink, extendedInfo sql.NullString
+	if err := row.Scan(&group.Group_Id, &description, &externalLink, &extendedInfo); err != nil {
+		return nil, err
+	}
+	group.Description = nullableStringToString(description)
+	group.ExternalLink = nullableStringToString(externalLink)
+	group.ExtendedInfo = nullableStringToString(extendedInfo)
+	return &group, nil
+}
+
+func execUpdateSecurityGroup(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, id int64) error {
+	_, err := tx.ExecContext(ctx, queryUpdateSecurityGroup,
+		sql.Named("param_group_id", id),
+		sql.Named("param_group_desc", d.Get("description").(string)),
+		sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+		sql.Named("param_ext_info", stringToNullString(d.Get("extended_info").(string))),
+	)
+	return err
+}
+
+func resourceSecurityGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	var groupID sql.NullInt64
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateSecurityGroup,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_group_desc", d.Get("description").(string)),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_group_id", sql.Out{Dest: &groupID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 || !groupID.Valid {
+			return fmt.Errorf("stored procedure returned failure status: %d or null ID", returnValue.Int64)
+		}
+		return execUpdateSecurityGroup(ctx, tx, d, groupID.Int64)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("group_id", int(groupID.Int64))
+	d.SetId(int64ToString(groupID.Int64))
+	return resourceSecurityGroupRead(ctx, d, m)
+}
+
+func resourceSecurityGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	group, err := scanSecurityGroup(db.QueryRowContext(ctx, queryGetSecurityGroup, sql.Named("param_group_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("group_id", group.Group_Id)
+	d.Set("description", group.Description)
+	d.Set("external_link", group.ExternalLink)
+	d.Set("extended_info", group.ExtendedInfo)
+	return nil
+}
+
+func resourceSecurityGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanSecurityGroup(tx.QueryRowContext(ctx, queryGetSecurityGroupForUpdate, sql.Named("param_group_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("security group %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "security group", map[string]interface{}{
+			"description":   current.Description,
+			"external_link": current.ExternalLink,
+			"extended_info": current.ExtendedInfo,
+		}); err != nil {
+			return err
+		}
+		return execUpdateSecurityGroup(ctx, tx, d, id)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceSecurityGroupRead(ctx, d, m)
+}
+
+func resourceSecurityGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteSecurityGroup,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_group_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceSecurityGroupImport accepts the group description.
+func resourceSecurityGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetSecurityGroupIdByDesc,
+		sql.Named("param_group_desc", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("security group %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
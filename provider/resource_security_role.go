@@ -0,0 +1,410 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// accessLevels lists the Access_Level rows in AL_Id order, starting at 1.
+var accessLevels = []string{"read", "write", "manager", "admin"}
+
+func accessLevelId(level string) int64 {
+	for i, l := range accessLevels {
+		if l == level {
+			return int64(i + 1)
+		}
+	}
+	return 0
+}
+
+func accessLevelName(id int64) string {
+	if id < 1 || int(id) > len(accessLevels) {
+		return ""
+	}
+	return accessLevels[id-1]
+}
+
+const (
+	// Security roles are rows of dbo.Users flagged Is_Role. A destroyed role is only
+	// deactivated, so an inactive one reads as gone.
+	queryGetSecurityRole = `
+		SELECT U.Username
+		FROM dbo.Users AS U
+		WHERE U.User_Id = @param_role_id
+		AND U.Is_Role = 1
+		AND U.Active = 1;
+		`
+
+	queryGetSecurityRoleForUpdate = `
+		SELECT U.Username
+		FROM dbo.Users AS U WITH (UPDLOCK, HOLDLOCK)
+		WHERE U.User_Id = @param_role_id
+		AND U.Is_Role = 1
+		AND U.Active = 1;
+		`
+
+	queryGetSecurityRoleIdByName = `
+		SELECT U.User_Id, U.Active
+		FROM dbo.Users AS U
+		WHERE U.Username = @param_role_name
+		AND U.Is_Role = 1;
+		`
+
+	queryLoadSecurityRoleAccess = `
+		SELECT US.Group_Id, US.Access_Level
+		FROM dbo.User_Security AS US
+		WHERE US.User_Id = @param_role_id
+		ORDER BY US.Group_Id;
+		`
+
+	// spEM_IEImportSecurityRoles creates the role on first use and grants it one group.
+	queryAddSecurityRoleAccess = `
+		SET XACT_ABORT ON;
+
+		DECLARE @group_desc			NVARCHAR(100),
+				@al_desc			NVARCHAR(100);
+
+		SET @group_desc = (
+			SELECT SG.Group_Desc FROM dbo.Security_Groups AS SG WHERE SG.Group_Id = @param_group_id);
+
+		IF @group_desc IS NULL
+			THROW 50000, 'security group not found', 1;
+
+		SET @al_desc = (
+			SELECT AL.AL_Desc FROM dbo.Access_Level AS AL WHERE AL.AL_Id = @param_al_id);
+
+		IF @al_desc IS NULL
+			THROW 50000, 'access level not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportSecurityRoles]
+				@SecurityRole = @param_role_name,
+				@SecurityRoleMember = NULL,
+				@SecurityGroupDesc = @group_desc,
+				@ALDesc = @al_desc,
+				@IsNTGroup = NULL,
+				@DomainName = NULL,
+				@InUserId = @param_user_id;
+		`
+
+	// spEM_IEImportSecurityRoles only ever adds a grant and PA has no procedure that revokes
+	// one, so revoking deletes the User_Security row directly, as the PA Administrator does.
+	queryRemoveSecurityRoleAccess = `
+		DELETE FROM dbo.User_Security
+		WHERE User_Id = @param_role_id
+		AND Group_Id = @param_group_id;
+		`
+
+	queryUpdateSecurityRole = `
+		UPDATE dbo.Users SET
+			Username = @param_role_name
+		WHERE User_Id = @param_role_id
+		AND Is_Role = 1;
+		`
+
+	queryReactivateSecurityRole = `
+		UPDATE dbo.Users SET
+			Active = 1
+		WHERE User_Id = @param_role_id
+		AND Is_Role = 1;
+		`
+
+	// Roles are rows of dbo.Users, which every audit trail references, and PA has no procedure
+	// that drops one, so destroying a role revokes its grants and deactivates it the way
+	// pa_user does. Members come from pa_user roles or the PA Administrator; deactivating a
+	// role that still has them would silently take their access away.
+	queryDeactivateSecurityRole = `
+		SET XACT_ABORT ON;
+
+		IF EXISTS (SELECT 1 FROM dbo.User_Role_Security AS URS WHERE URS.Role_User_Id = @param_role_id)
+			THROW 50000, 'security role still has members; remove it from their roles first', 1;
+
+		DELETE FROM dbo.User_Security WHERE User_Id = @param_role_id;
+		UPDATE dbo.Users SET Active = 0 WHERE User_Id = @param_role_id AND Is_Role = 1;
+		`
+)
+
+func resourceSecurityRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSecurityRoleCreate,
+		ReadContext:   resourceSecurityRoleRead,
+		UpdateContext: resourceSecurityRoleUpdate,
+		DeleteContext: resourceSecurityRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSecurityRoleImport,
+		},
+		CustomizeDiff: resourceSecurityRoleCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"role_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Username of the role.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"access": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "Every group the role can access. Grants added outside Terraform are removed.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"security_group_id": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"access_level": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(accessLevels, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceSecurityRoleCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	seen := map[int]bool{}
+	for _, v := range d.Get("access").(*schema.Set).List() {
+		groupID := v.(map[string]interface{})["security_group_id"].(int)
+		if groupID != 0 && seen[groupID] {
+			return fmt.Errorf("security group %d has more than one access block", groupID)
+		}
+		seen[groupID] = true
+	}
+	return nil
+}
+
+// loadAccessLevels returns User_Security rows as access levels keyed by the first column.
+func loadAccessLevels(rows *sql.Rows, err error) (map[int]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	access := map[int]string{}
+	for rows.Next() {
+		var id, alID int64
+		if err := rows.Scan(&id, &alID); err != nil {
+			return nil, err
+		}
+		access[int(id)] = accessLevelName(alID)
+	}
+	return access, rows.Err()
+}
+
+// syncSecurityRoleAccess grants and revokes so the role matches desired exactly. A changed
+// level is revoked and granted again. roleID is 0 while the role does not exist yet.
+func syncSecurityRoleAccess(ctx context.Context, tx *sql.Tx, roleID int64, roleName string, desired *schema.Set) error {
+	userId := 1
+
+	current, err := loadAccessLevels(tx.QueryContext(ctx, queryLoadSecurityRoleAccess, sql.Named("param_role_id", roleID)))
+	if err != nil {
+		return err
+	}
+
+	wanted := map[int]string{}
+	for _, v := range desired.List() {
+		block := v.(map[string]interface{})
+		wanted[block["security_group_id"].(int)] = block["access_level"].(string)
+	}
+
+	for groupID, level := range current {
+		if wanted[groupID] == level {
+			continue
+		}
+		_, err := tx.ExecContext(ctx, queryRemoveSecurityRoleAccess,
+			sql.Named("param_role_id", roleID),
+			sql.Named("param_group_id", int64(groupID)),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to revoke security group %d from role %q: %w", groupID, roleName, err)
+		}
+	}
+
+	for groupID, level := range wanted {
+		if current[groupID] == level {
+			continue
+		}
+		var returnValue sql.NullInt64
+		_, err := tx.ExecContext(ctx, queryAddSecurityRoleAccess,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_role_name", roleName),
+			sql.Named("param_group_id", int64(groupID)),
+			sql.Named("param_al_id", accessLevelId(level)),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to grant security group %d to role %q: %w", groupID, roleName, err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status %d granting security group %d", returnValue.Int64, groupID)
+		}
+	}
+	return nil
+}
+
+func resourceSecurityRoleCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	var roleID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var active sql.NullBool
+		err := tx.QueryRowContext(ctx, queryGetSecurityRoleIdByName, sql.Named("param_role_name", name)).Scan(&roleID, &active)
+		if err == nil {
+			if active.Bool {
+				return fmt.Errorf("security role %q already exists; import it instead", name)
+			}
+			// A destroyed pa_security_role is only deactivated, so re-creating it takes the
+			// row back. Its grants were revoked on destroy.
+			if _, err := tx.ExecContext(ctx, queryReactivateSecurityRole, sql.Named("param_role_id", roleID)); err != nil {
+				return fmt.Errorf("failed to reactivate security role %q: %w", name, err)
+			}
+			return syncSecurityRoleAccess(ctx, tx, roleID, name, d.Get("access").(*schema.Set))
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := syncSecurityRoleAccess(ctx, tx, 0, name, d.Get("access").(*schema.Set)); err != nil {
+			return err
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetSecurityRoleIdByName, sql.Named("param_role_name", name)).Scan(&roleID, &active)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("security role %q was not created", name)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("role_id", int(roleID))
+	d.SetId(int64ToString(roleID))
+	return resourceSecurityRoleRead(ctx, d, m)
+}
+
+func resourceSecurityRoleRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var name sql.NullString
+	err = db.QueryRowContext(ctx, queryGetSecurityRole, sql.Named("param_role_id", id)).Scan(&name)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	current, err := loadAccessLevels(db.QueryContext(ctx, queryLoadSecurityRoleAccess, sql.Named("param_role_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	access := make([]interface{}, 0, len(current))
+	for groupID, level := range current {
+		access = append(access, map[string]interface{}{
+			"security_group_id": groupID,
+			"access_level":      level,
+		})
+	}
+
+	d.Set("role_id", id)
+	d.Set("name", nullableStringToString(name))
+	if err := d.Set("access", access); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceSecurityRoleUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	name := d.Get("name").(string)
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		if d.HasChange("name") {
+			var current sql.NullString
+			err := tx.QueryRowContext(ctx, queryGetSecurityRoleForUpdate, sql.Named("param_role_id", id)).Scan(&current)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("security role %d no longer exists", id)
+			}
+			if err != nil {
+				return err
+			}
+			if err := checkUnchanged(d, "security role", map[string]interface{}{
+				"name": nullableStringToString(current),
+			}); err != nil {
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, queryUpdateSecurityRole,
+				sql.Named("param_role_id", id),
+				sql.Named("param_role_name", name),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return syncSecurityRoleAccess(ctx, tx, id, name, d.Get("access").(*schema.Set))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceSecurityRoleRead(ctx, d, m)
+}
+
+func resourceSecurityRoleDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeactivateSecurityRole, sql.Named("param_role_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceSecurityRoleImport accepts the role name.
+func resourceSecurityRoleImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	var active sql.NullBool
+	err := getDB(m).QueryRowContext(ctx, queryGetSecurityRoleIdByName,
+		sql.Named("param_role_name", d.Id()),
+	).Scan(&id, &active)
+	if err == sql.ErrNoRows || (err == nil && !active.Bool) {
+		return nil, fmt.Errorf("security role %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type Specification struct {
+	Spec_Id      int64
+	Prop_Id      int64
+	Description  string
+	Path         string
+	DataType     string
+	Precision    int64
+	EngUnits     string
+	Tag          string
+	ExternalLink string
+	ExtendedInfo string
+}
+
+const (
+	queryGetSpecification = `
+		SELECT	S.Spec_Id, S.Prop_Id, S.Spec_Desc, PP.Prop_Desc + ' / ' + S.Spec_Desc, DT.Data_Type_Desc,
+				S.Spec_Precision, S.Eng_Units, S.Tag, S.External_Link, S.Extended_Info
+		FROM dbo.Specifications AS S
+		JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = S.Prop_Id
+		LEFT JOIN dbo.Data_Type AS DT ON DT.Data_Type_Id = S.Data_Type_Id
+		WHERE S.Spec_Id = @param_spec_id;
+		`
+
+	queryGetSpecificationForUpdate = `
+		SELECT	S.Spec_Id, S.Prop_Id, S.Spec_Desc, PP.Prop_Desc + ' / ' + S.Spec_Desc, DT.Data_Type_Desc,
+				S.Spec_Precision, S.Eng_Units, S.Tag, S.External_Link, S.Extended_Info
+		FROM dbo.Specifications AS S WITH (UPDLOCK, HOLDLOCK)
+		JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = S.Prop_Id
+		LEFT JOIN dbo.Data_Type AS DT ON DT.Data_Type_Id = S.Data_Type_Id
+		WHERE S.Spec_Id = @param_spec_id;
+		`
+
+	queryGetSpecificationIdByPath = `
+		SELECT S.Spec_Id
+		FROM dbo.Specifications AS S
+		JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = S.Prop_Id
+		WHERE PP.Prop_Desc = @param_prop_desc
+		AND S.Spec_Desc = @param_spec_desc;
+		`
+
+	// Renames happen before the procedure runs, because spEM_IEImportSpecVariables finds the
+	// specification to update by its property and description.
+	queryRenameSpecification = `
+		UPDATE dbo.Specifications SET
+			Spec_Desc = @param_spec_desc
+		WHERE Spec_Id = @param_spec_id;
+		`
+
+	// spEM_IEImportSpecVariables creates or updates the specification named by property and
+	// description.
+	queryImportSpecification = `
+		SET XACT_ABORT ON;
+
+		DECLARE @prop_desc			NVARCHAR(80),
+				@msg				NVARCHAR(2048);
+
+		SET @prop_desc = (
+			SELECT PP.Prop_Desc FROM dbo.Product_Properties AS PP WHERE PP.Prop_Id = @param_prop_id);
+
+		IF @prop_desc IS NULL
+			THROW 50000, 'product property not found', 1;
+
+		IF NOT EXISTS (SELECT 1 FROM dbo.Data_Type WHERE Data_Type_Desc = @param_data_type)
+		BEGIN
+			SET @msg = N'Data_Type_Desc not found: ' + @param_data_type;
+			THROW 50000, @msg, 1;
+		END
+
+		IF @param_spec_id IS NULL AND EXISTS (
+			SELECT 1 FROM dbo.Specifications AS S
+			WHERE S.Prop_Id = @param_prop_id AND S.Spec_Desc = @param_spec_desc)
+			THROW 50000, 'a specification with this description already exists; import it instead', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportSpecVariables]
+				@Prop_Desc = @prop_desc,
+				@Spec_Desc = @param_spec_desc,
+				@Data_Type_Desc = @param_data_type,
+				@Tag = @param_tag,
+				@Eng_Units = @param_eng_units,
+				@External_Link = @param_ext_link,
+				@Extended_Info = @param_ext_info,
+				@Comment = NULL,
+				@Spec_Precision = @param_precision,
+				@User_Id = @param_user_id;
+
+		SET @out_Spec_Id = (
+			SELECT S.Spec_Id FROM dbo.Specifications AS S
+			WHERE S.Prop_Id = @param_prop_id AND S.Spec_Desc = @param_spec_desc);
+		`
+
+	queryDeleteSpecification = `
+		EXEC	@return_value = [dbo].[spEM_DropSpec]
+				@Spec_Id = @param_spec_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceSpecification() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSpecificationCreate,
+		ReadContext:   resourceSpecificationRead,
+		UpdateContext: resourceSpecificationUpdate,
+		DeleteContext: resourceSpecificationDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSpecificationImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"specification_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"property_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "Spec_Desc, unique within the property.",
+				ValidateFunc: validateVarchar50(),
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "\"Property / Spec\", the form the import procedures look specifications up by.",
+			},
+			"data_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Data_Type_Desc from dbo.Data_Type.",
+			},
+			"precision": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntBetween(0, 10),
+			},
+			"eng_units": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(0, 15),
+			},
+			"tag": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar50(),
+			},
+			"external_link": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"extended_info": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+		},
+	}
+}
+
+func scanSpecification(row interface{ Scan(...interface{}) error }) (*Specification, error) {
+	var spec Specification
+	var propID, precision sql.NullInt64
+	var description, path, dataType, engUnits, tag, externalLink, extendedInfo sql.NullString
+	if err := row.Scan(&spec.Spec_Id, &propID, &description, &path, &dataType,
+		&precision, &engUnits, &tag, &externalLink, &extendedInfo); err != nil {
+		return nil, err
+	}
+	spec.Prop_Id = nullableIdToInt64(propID)
+	spec.Description = nullableStringToString(description)
+	spec.Path = nullableStringToString(path)
+	spec.DataType = nullableStringToString(dataType)
+	spec.Precision = precision.Int64
+	spec.EngUnits = nullableStringToString(engUnits)
+	spec.Tag = nullableStringToString(tag)
+	spec.ExternalLink = nullableStringToString(externalLink)
+	spec.ExtendedInfo = nullableStringToString(extendedInfo)
+	return &spec, nil
+}
+
+// execImportSpecification runs spEM_IEImportSpecVariables for a new specification (id 0) or an
+// existing one, and returns the specification's ID.
+func execImportSpecification(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, id int64) (int64, error) {
+	userId := 1
+
+	precision := sql.NullString{}
+	if v, ok := d.GetOk("precision"); ok {
+		precision = sql.NullString{String: strconv.Itoa(v.(int)), Valid: true}
+	}
+
+	var returnValue sql.NullInt64
+	var outSpecID sql.NullInt64
+	_, err := tx.ExecContext(ctx, queryImportSpecification,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_spec_id", idToNullInt64(id)),
+		sql.Named("param_prop_id", int64(d.Get("property_id").(int))),
+		sql.Named("param_spec_desc", d.Get("description").(string)),
+		sql.Named("param_data_type", d.Get("data_type").(string)),
+		sql.Named("param_precision", precision),
+		sql.Named("param_eng_units", stringToNullString(d.Get("eng_units").(string))),
+		sql.Named("param_tag", stringToNullString(d.Get("tag").(string))),
+		sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+		sql.Named("param_ext_info", stringToNullString(d.Get("extended_info").(string))),
+		sql.Named("param_user_id", userId),
+		sql.Named("out_Spec_Id", sql.Out{Dest: &outSpecID}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	if returnValue.Int64 != 0 || !outSpecID.Valid {
+		return 0, fmt.Errorf(
+			"stored procedure returned failure status: return_value=%v, outSpecID.Valid=%v",
+			returnValue.Int64, outSpecID.Valid)
+	}
+	return outSpecID.Int64, nil
+}
+
+func resourceSpecificationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var id int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var err error
+		id, err = execImportSpecification(ctx, tx, d, 0)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("specification_id", int(id))
+	d.SetId(int64ToString(id))
+	return resourceSpecificationRead(ctx, d, m)
+}
+
+func resourceSpecificationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	spec, err := scanSpecification(db.QueryRowContext(ctx, queryGetSpecification, sql.Named("param_spec_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("specification_id", spec.Spec_Id)
+	d.Set("property_id", spec.Prop_Id)
+	d.Set("description", spec.Description)
+	d.Set("path", spec.Path)
+	d.Set("data_type", spec.DataType)
+	d.Set("precision", spec.Precision)
+	d.Set("eng_units", spec.EngUnits)
+	d.Set("tag", spec.Tag)
+	d.Set("external_link", spec.ExternalLink)
+	d.Set("extended_info", spec.ExtendedInfo)
+	return nil
+}
+
+func resourceSpecificationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanSpecification(tx.QueryRowContext(ctx, queryGetSpecificationForUpdate, sql.Named("param_spec_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("specification %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "specification", map[string]interface{}{
+			"description":   current.Description,
+			"data_type":     current.DataType,
+			"precision":     current.Precision,
+			"eng_units":     current.EngUnits,
+			"tag":           current.Tag,
+			"external_link": current.ExternalLink,
+			"extended_info": current.ExtendedInfo,
+		}); err != nil {
+			return err
+		}
+
+		if d.HasChange("description") {
+			_, err := tx.ExecContext(ctx, queryRenameSpecification,
+				sql.Named("param_spec_id", id),
+				sql.Named("param_spec_desc", d.Get("description").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = execImportSpecification(ctx, tx, d, id)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceSpecificationRead(ctx, d, m)
+}
+
+func resourceSpecificationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteSpecification,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_spec_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceSpecificationImport accepts "Property / Spec", the same form variables use.
+func resourceSpecificationImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), " / ")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("unexpected import ID %q, expected \"Property / Spec\"", d.Id())
+	}
+
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetSpecificationIdByPath,
+		sql.Named("param_prop_desc", parts[0]),
+		sql.Named("param_spec_desc", parts[1]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("specification %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
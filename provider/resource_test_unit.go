@@ -35,11 +35,11 @@ func resourceTestUnit() *schema.Resource {
 }
 
 func getConnection(d *schema.ResourceData, m interface{}) (*sql.DB, error) {
-	db, ok := m.(*sql.DB)
+	meta, ok := m.(*ProviderMeta)
 	if !ok {
 		return nil, fmt.Errorf("failed to get database connection from provider metadata")
 	}
-	return db, nil
+	return meta.DB, nil
 }
 
 func resourceTestUnitCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
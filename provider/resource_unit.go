@@ -0,0 +1,449 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type Unit struct {
+	Unit_Id                  int64
+	Line_Id                  int64
+	Description              string
+	DescriptionGlobal        string
+	MasterUnit_Id            int64
+	UnitType                 string
+	EquipmentType            string
+	ExtendedInfo             string
+	ExternalLink             string
+	SecurityGroup_Id         int64
+	ChainStartTime           bool
+	UsesStartTime            bool
+	ProductionRateSpec_Id    int64
+	DowntimePercentSpec_Id   int64
+	EfficiencyPercentSpec_Id int64
+	WastePercentSpec_Id      int64
+}
+
+const (
+	queryGetUnit = `
+		SELECT	PUB.PU_Id, PUB.PL_Id, PUB.PU_Desc, PUB.PU_Desc_Global, PUB.Master_Unit, UT.UT_Desc,
+				PUB.Equipment_Type, PUB.Extended_Info, PUB.External_Link, PUB.Group_Id,
+				PUB.Chain_Start_Time, PUB.Uses_Start_Time, PUB.Production_Rate_Specification,
+				PUB.Downtime_Percent_Specification, PUB.Efficiency_Percent_Specification,
+				PUB.Waste_Percent_Specification
+		FROM dbo.Prod_Units_Base AS PUB
+		LEFT JOIN dbo.Unit_Types AS UT ON UT.Unit_Type_Id = PUB.Unit_Type_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+		`
+
+	queryGetUnitForUpdate = `
+		SELECT	PUB.PU_Id, PUB.PL_Id, PUB.PU_Desc, PUB.PU_Desc_Global, PUB.Master_Unit, UT.UT_Desc,
+				PUB.Equipment_Type, PUB.Extended_Info, PUB.External_Link, PUB.Group_Id,
+				PUB.Chain_Start_Time, PUB.Uses_Start_Time, PUB.Production_Rate_Specification,
+				PUB.Downtime_Percent_Specification, PUB.Efficiency_Percent_Specification,
+				PUB.Waste_Percent_Specification
+		FROM dbo.Prod_Units_Base AS PUB WITH (UPDLOCK, HOLDLOCK)
+		LEFT JOIN dbo.Unit_Types AS UT ON UT.Unit_Type_Id = PUB.Unit_Type_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+		`
+
+	queryGetUnitIdByPath = `
+		SELECT PUB.PU_Id
+		FROM dbo.Prod_Units_Base AS PUB
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PLB.PL_Desc = @param_pl_desc
+		AND PUB.PU_Desc = @param_pu_desc;
+		`
+
+	// spEM_IEImportUnits works on descriptions, so the line, department, master unit and
+	// security group are resolved from their IDs first.
+	queryCreateUnit = `
+		SET XACT_ABORT ON;
+
+		DECLARE @dept_desc			NVARCHAR(100),
+				@pl_desc			NVARCHAR(100),
+				@master_desc		NVARCHAR(100),
+				@sg_desc			NVARCHAR(100);
+
+		SELECT	@pl_desc = PLB.PL_Desc, @dept_desc = DB.Dept_Desc
+		FROM dbo.Prod_Lines_Base AS PLB
+		JOIN dbo.Departments_Base AS DB ON DB.Dept_Id = PLB.Dept_Id
+		WHERE PLB.PL_Id = @param_pl_id;
+
+		IF @pl_desc IS NULL
+			THROW 50000, 'line not found', 1;
+
+		SET @master_desc = (
+			SELECT PUB.PU_Desc FROM dbo.Prod_Units_Base AS PUB WHERE PUB.PU_Id = @param_master_unit_id);
+
+		SET @sg_desc = (
+			SELECT SG.Group_Desc FROM dbo.Security_Groups AS SG WHERE SG.Group_Id = @param_group_id);
+
+		EXEC	@return_value = [dbo].[spEM_IEImportUnits]
+				@Dept_Desc = @dept_desc,
+				@PL_Desc = @pl_desc,
+				@PU_Desc = @param_pu_desc,
+				@Master_Unit_Desc = @master_desc,
+				@External_Link = @param_ext_link,
+				@Group_Desc = @sg_desc,
+				@Extended_Info = @param_ext_info,
+				@UseStartTime = @param_use_start_time,
+				@User_Id = @param_user_id;
+
+		SET @out_PU_Id = (
+			SELECT PUB.PU_Id FROM dbo.Prod_Units_Base AS PUB
+			WHERE PUB.PL_Id = @param_pl_id AND PUB.PU_Desc = @param_pu_desc);
+		`
+
+	// The import procedure doesn't cover every column, so create finishes with the same
+	// UPDATE that Update uses.
+	queryUpdateUnit = `
+		SET XACT_ABORT ON;
+
+		DECLARE @ut_id INT = NULL;
+
+		IF @param_unit_type IS NOT NULL
+		BEGIN
+			SET @ut_id = (SELECT UT.Unit_Type_Id FROM dbo.Unit_Types AS UT WHERE UT.UT_Desc = @param_unit_type);
+			IF @ut_id IS NULL
+				THROW 50000, 'unit type not found in dbo.Unit_Types', 1;
+		END
+
+		UPDATE dbo.Prod_Units_Base SET
+			PU_Desc = @param_pu_desc,
+			PU_Desc_Global = @param_pu_desc_global,
+			Master_Unit = @param_master_unit_id,
+			Unit_Type_Id = @ut_id,
+			Equipment_Type = @param_equipment_type,
+			Extended_Info = @param_ext_info,
+			External_Link = @param_ext_link,
+			Group_Id = @param_group_id,
+			Chain_Start_Time = @param_chain_start_time,
+			Uses_Start_Time = @param_use_start_time,
+			Production_Rate_Specification = @param_prod_rate_spec_id,
+			Downtime_Percent_Specification = @param_downtime_spec_id,
+			Efficiency_Percent_Specification = @param_efficiency_spec_id,
+			Waste_Percent_Specification = @param_waste_spec_id
+		WHERE PU_Id = @param_pu_id;
+		`
+
+	queryDeleteUnit = `
+		EXEC	@return_value = [dbo].[spEM_DropUnit]
+				@PU_Id = @param_pu_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceUnit() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUnitCreate,
+		ReadContext:   resourceUnitRead,
+		UpdateContext: resourceUnitUpdate,
+		DeleteContext: resourceUnitDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUnitImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"unit_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"line_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "PU_Desc, the local description.",
+				ValidateFunc: validateTitle(),
+			},
+			"description_global": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTitle(),
+			},
+			"master_unit_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"unit_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "UT_Desc from dbo.Unit_Types.",
+			},
+			"equipment_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar50(),
+			},
+			"extended_info": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"external_link": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"security_group_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"chain_start_time": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"uses_start_time": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"production_rate_specification_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Spec_Id of the production rate specification.",
+			},
+			"downtime_percent_specification_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"efficiency_percent_specification_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"waste_percent_specification_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func scanUnit(row interface{ Scan(...interface{}) error }) (*Unit, error) {
+	var unit Unit
+	var lineID, masterUnitID, securityGroupID, chainStartTime, usesStartTime sql.NullInt64
+	var prodRateSpecID, downtimeSpecID, efficiencySpecID, wasteSpecID sql.NullInt64
+	var description, descriptionGlobal, unitType, equipmentType, extendedInfo, externalLink sql.NullString
+	if err := row.Scan(&unit.Unit_Id, &lineID, &description, &descriptionGlobal, &masterUnitID, &unitType,
+		&equipmentType, &extendedInfo, &externalLink, &securityGroupID,
+		&chainStartTime, &usesStartTime, &prodRateSpecID, &downtimeSpecID, &efficiencySpecID,
+		&wasteSpecID); err != nil {
+		return nil, err
+	}
+	unit.Line_Id = nullableIdToInt64(lineID)
+	unit.Description = nullableStringToString(description)
+	unit.DescriptionGlobal = nullableStringToString(descriptionGlobal)
+	unit.MasterUnit_Id = nullableIdToInt64(masterUnitID)
+	unit.UnitType = nullableStringToString(unitType)
+	unit.EquipmentType = nullableStringToString(equipmentType)
+	unit.ExtendedInfo = nullableStringToString(extendedInfo)
+	unit.ExternalLink = nullableStringToString(externalLink)
+	unit.SecurityGroup_Id = nullableIdToInt64(securityGroupID)
+	unit.ChainStartTime = chainStartTime.Valid && chainStartTime.Int64 != 0
+	unit.UsesStartTime = usesStartTime.Valid && usesStartTime.Int64 != 0
+	unit.ProductionRateSpec_Id = nullableIdToInt64(prodRateSpecID)
+	unit.DowntimePercentSpec_Id = nullableIdToInt64(downtimeSpecID)
+	unit.EfficiencyPercentSpec_Id = nullableIdToInt64(efficiencySpecID)
+	unit.WastePercentSpec_Id = nullableIdToInt64(wasteSpecID)
+	return &unit, nil
+}
+
+func execUpdateUnit(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, id int64) error {
+	_, err := tx.ExecContext(ctx, queryUpdateUnit,
+		sql.Named("param_pu_id", id),
+		sql.Named("param_pu_desc", d.Get("description").(string)),
+		sql.Named("param_pu_desc_global", stringToNullString(d.Get("description_global").(string))),
+		sql.Named("param_master_unit_id", idToNullInt64(int64(d.Get("master_unit_id").(int)))),
+		sql.Named("param_unit_type", stringToNullString(d.Get("unit_type").(string))),
+		sql.Named("param_equipment_type", stringToNullString(d.Get("equipment_type").(string))),
+		sql.Named("param_ext_info", stringToNullString(d.Get("extended_info").(string))),
+		sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+		sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+		sql.Named("param_chain_start_time", boolToInt64(d.Get("chain_start_time").(bool))),
+		sql.Named("param_use_start_time", boolToInt64(d.Get("uses_start_time").(bool))),
+		sql.Named("param_prod_rate_spec_id", idToNullInt64(int64(d.Get("production_rate_specification_id").(int)))),
+		sql.Named("param_downtime_spec_id", idToNullInt64(int64(d.Get("downtime_percent_specification_id").(int)))),
+		sql.Named("param_efficiency_spec_id", idToNullInt64(int64(d.Get("efficiency_percent_specification_id").(int)))),
+		sql.Named("param_waste_spec_id", idToNullInt64(int64(d.Get("waste_percent_specification_id").(int)))),
+	)
+	return err
+}
+
+func resourceUnitCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	lineID := int64(d.Get("line_id").(int))
+	description := d.Get("description").(string)
+	userId := 1
+
+	var returnValue sql.NullInt64
+	var outPUID sql.NullInt64
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateUnit,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_pl_id", lineID),
+			sql.Named("param_pu_desc", description),
+			sql.Named("param_master_unit_id", idToNullInt64(int64(d.Get("master_unit_id").(int)))),
+			sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+			sql.Named("param_ext_info", stringToNullString(d.Get("extended_info").(string))),
+			sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+			sql.Named("param_use_start_time", int64ToString(boolToInt64(d.Get("uses_start_time").(bool)))),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_PU_Id", sql.Out{Dest: &outPUID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 || !outPUID.Valid {
+			return fmt.Errorf(
+				"stored procedure returned failure status: return_value=%v, outPUID.Valid=%v",
+				returnValue.Int64, outPUID.Valid)
+		}
+		return execUpdateUnit(ctx, tx, d, outPUID.Int64)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("unit_id", int(outPUID.Int64))
+	d.SetId(int64ToString(outPUID.Int64))
+	return resourceUnitRead(ctx, d, m)
+}
+
+func resourceUnitRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	unit, err := scanUnit(db.QueryRowContext(ctx, queryGetUnit, sql.Named("param_pu_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("unit_id", unit.Unit_Id)
+	d.Set("line_id", unit.Line_Id)
+	d.Set("description", unit.Description)
+	d.Set("description_global", unit.DescriptionGlobal)
+	d.Set("master_unit_id", unit.MasterUnit_Id)
+	d.Set("unit_type", unit.UnitType)
+	d.Set("equipment_type", unit.EquipmentType)
+	d.Set("extended_info", unit.ExtendedInfo)
+	d.Set("external_link", unit.ExternalLink)
+	d.Set("security_group_id", unit.SecurityGroup_Id)
+	d.Set("chain_start_time", unit.ChainStartTime)
+	d.Set("uses_start_time", unit.UsesStartTime)
+	d.Set("production_rate_specification_id", unit.ProductionRateSpec_Id)
+	d.Set("downtime_percent_specification_id", unit.DowntimePercentSpec_Id)
+	d.Set("efficiency_percent_specification_id", unit.EfficiencyPercentSpec_Id)
+	d.Set("waste_percent_specification_id", unit.WastePercentSpec_Id)
+	return nil
+}
+
+func resourceUnitUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanUnit(tx.QueryRowContext(ctx, queryGetUnitForUpdate, sql.Named("param_pu_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("unit %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "unit", map[string]interface{}{
+			"description":                         current.Description,
+			"description_global":                  current.DescriptionGlobal,
+			"master_unit_id":                      current.MasterUnit_Id,
+			"unit_type":                           current.UnitType,
+			"equipment_type":                      current.EquipmentType,
+			"extended_info":                       current.ExtendedInfo,
+			"external_link":                       current.ExternalLink,
+			"security_group_id":                   current.SecurityGroup_Id,
+			"chain_start_time":                    current.ChainStartTime,
+			"uses_start_time":                     current.UsesStartTime,
+			"production_rate_specification_id":    current.ProductionRateSpec_Id,
+			"downtime_percent_specification_id":   current.DowntimePercentSpec_Id,
+			"efficiency_percent_specification_id": current.EfficiencyPercentSpec_Id,
+			"waste_percent_specification_id":      current.WastePercentSpec_Id,
+		}); err != nil {
+			return err
+		}
+		return execUpdateUnit(ctx, tx, d, id)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceUnitRead(ctx, d, m)
+}
+
+func resourceUnitDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteUnit,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_pu_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceUnitImport accepts "Line/Unit", e.g. terraform import pa_unit.filler "Line1/Filler".
+func resourceUnitImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Line", "Unit")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetUnitIdByPath,
+		sql.Named("param_pl_desc", parts[0]),
+		sql.Named("param_pu_desc", parts[1]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("unit %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type UnitGroup struct {
+	UnitGroup_Id      int64
+	Unit_Id           int64
+	Description       string
+	DescriptionGlobal string
+	Order             int64
+	ExternalLink      string
+	SecurityGroup_Id  int64
+}
+
+const (
+	queryGetUnitGroup = `
+		SELECT	PUG.PUG_Id, PUG.PU_Id, PUG.PUG_Desc, PUG.PUG_Desc_Global, PUG.PUG_Order,
+				PUG.External_Link, PUG.Group_Id
+		FROM dbo.PU_Groups AS PUG
+		WHERE PUG.PUG_Id = @param_pug_id;
+		`
+
+	queryGetUnitGroupForUpdate = `
+		SELECT	PUG.PUG_Id, PUG.PU_Id, PUG.PUG_Desc, PUG.PUG_Desc_Global, PUG.PUG_Order,
+				PUG.External_Link, PUG.Group_Id
+		FROM dbo.PU_Groups AS PUG WITH (UPDLOCK, HOLDLOCK)
+		WHERE PUG.PUG_Id = @param_pug_id;
+		`
+
+	queryGetUnitGroupIdByPath = `
+		SELECT PUG.PUG_Id
+		FROM dbo.PU_Groups AS PUG
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = PUG.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PLB.PL_Desc = @param_pl_desc
+		AND PUB.PU_Desc = @param_pu_desc
+		AND PUG.PUG_Desc = @param_pug_desc;
+		`
+
+	queryCreateUnitGroup = `
+		SET XACT_ABORT ON;
+
+		DECLARE @pl_desc			NVARCHAR(50),
+				@pu_desc			NVARCHAR(50),
+				@sg_desc			NVARCHAR(50);
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc
+		FROM dbo.Prod_Units_Base AS PUB
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+
+		IF @pu_desc IS NULL
+			THROW 50000, 'unit not found', 1;
+
+		SET @sg_desc = (
+			SELECT SG.Group_Desc FROM dbo.Security_Groups AS SG WHERE SG.Group_Id = @param_group_id);
+
+		EXEC	@return_value = [dbo].[spEM_IEImportProductionGroups]
+				@PL_Desc = @pl_desc,
+				@PU_Desc = @pu_desc,
+				@PUG_Desc = @param_pug_desc,
+				@External_Link = @param_ext_link,
+				@Group_Desc = @sg_desc,
+				@User_Id = @param_user_id;
+
+		SET @out_PUG_Id = (
+			SELECT PUG.PUG_Id FROM dbo.PU_Groups AS PUG
+			WHERE PUG.PU_Id = @param_pu_id AND PUG.PUG_Desc = @param_pug_desc);
+		`
+
+	// Every column is written as configured, so removing order or description_global clears it.
+	queryUpdateUnitGroup = `
+		UPDATE dbo.PU_Groups SET
+			PUG_Desc = @param_pug_desc,
+			PUG_Desc_Global = @param_pug_desc_global,
+			PUG_Order = @param_pug_order,
+			External_Link = @param_ext_link,
+			Group_Id = @param_group_id
+		WHERE PUG_Id = @param_pug_id;
+		`
+
+	queryDeleteUnitGroup = `
+		EXEC	@return_value = [dbo].[spEM_DropPUG]
+				@PUG_Id = @param_pug_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+func resourceUnitGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUnitGroupCreate,
+		ReadContext:   resourceUnitGroupRead,
+		UpdateContext: resourceUnitGroupUpdate,
+		DeleteContext: resourceUnitGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUnitGroupImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"unit_group_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"unit_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "PUG_Desc, the local description.",
+				ValidateFunc: validateTitle(),
+			},
+			"description_global": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateTitle(),
+			},
+			"order": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "PUG_Order, the position of the group within its unit.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"external_link": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateVarchar255(),
+			},
+			"security_group_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func scanUnitGroup(row interface{ Scan(...interface{}) error }) (*UnitGroup, error) {
+	var group UnitGroup
+	var unitID, order, securityGroupID sql.NullInt64
+	var description, descriptionGlobal, externalLink sql.NullString
+	if err := row.Scan(&group.UnitGroup_Id, &unitID, &description, &descriptionGlobal, &order,
+		&externalLink, &securityGroupID); err != nil {
+		return nil, err
+	}
+	group.Unit_Id = nullableIdToInt64(unitID)
+	group.Description = nullableStringToString(description)
+	group.DescriptionGlobal = nullableStringToString(descriptionGlobal)
+	group.Order = nullableIdToInt64(order)
+	group.ExternalLink = nullableStringToString(externalLink)
+	group.SecurityGroup_Id = nullableIdToInt64(securityGroupID)
+	return &group, nil
+}
+
+func execUpdateUnitGroup(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, id int64) error {
+	_, err := tx.ExecContext(ctx, queryUpdateUnitGroup,
+		sql.Named("param_pug_id", id),
+		sql.Named("param_pug_desc", d.Get("description").(string)),
+		sql.Named("param_pug_desc_global", stringToNullString(d.Get("description_global").(string))),
+		sql.Named("param_pug_order", idToNullInt64(int64(d.Get("order").(int)))),
+		sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+		sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+	)
+	return err
+}
+
+func resourceUnitGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unitID := int64(d.Get("unit_id").(int))
+	description := d.Get("description").(string)
+	userId := 1
+
+	var returnValue sql.NullInt64
+	var outPUGID sql.NullInt64
+
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryCreateUnitGroup,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_pu_id", unitID),
+			sql.Named("param_pug_desc", description),
+			sql.Named("param_ext_link", stringToNullString(d.Get("external_link").(string))),
+			sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+			sql.Named("param_user_id", userId),
+			sql.Named("out_PUG_Id", sql.Out{Dest: &outPUGID}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 || !outPUGID.Valid {
+			return fmt.Errorf(
+				"stored procedure returned failure status: return_value=%v, outPUGID.Valid=%v",
+				returnValue.Int64, outPUGID.Valid)
+		}
+		return execUpdateUnitGroup(ctx, tx, d, outPUGID.Int64)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("unit_group_id", int(outPUGID.Int64))
+	d.SetId(int64ToString(outPUGID.Int64))
+	return resourceUnitGroupRead(ctx, d, m)
+}
+
+func resourceUnitGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	group, err := scanUnitGroup(db.QueryRowContext(ctx, queryGetUnitGroup, sql.Named("param_pug_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("unit_group_id", group.UnitGroup_Id)
+	d.Set("unit_id", group.Unit_Id)
+	d.Set("description", group.Description)
+	d.Set("description_global", group.DescriptionGlobal)
+	d.Set("order", group.Order)
+	d.Set("external_link", group.ExternalLink)
+	d.Set("security_group_id", group.SecurityGroup_Id)
+	return nil
+}
+
+func resourceUnitGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanUnitGroup(tx.QueryRowContext(ctx, queryGetUnitGroupForUpdate, sql.Named("param_pug_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("unit group %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "unit group", map[string]interface{}{
+			"description":        current.Description,
+			"description_global": current.DescriptionGlobal,
+			"order":              current.Order,
+			"external_link":      current.ExternalLink,
+			"security_group_id":  current.SecurityGroup_Id,
+		}); err != nil {
+			return err
+		}
+		return execUpdateUnitGroup(ctx, tx, d, id)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceUnitGroupRead(ctx, d, m)
+}
+
+func resourceUnitGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteUnitGroup,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_pug_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceUnitGroupImport accepts "Line/Unit/Group".
+func resourceUnitGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Line", "Unit", "Group")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetUnitGroupIdByPath,
+		sql.Named("param_pl_desc", parts[0]),
+		sql.Named("param_pu_desc", parts[1]),
+		sql.Named("param_pug_desc", parts[2]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("unit group %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
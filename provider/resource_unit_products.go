@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetUnitExists = `
+		SELECT PUB.PU_Id
+		FROM dbo.Prod_Units_Base AS PUB
+		WHERE PUB.PU_Id = @param_pu_id;
+		`
+
+	queryLoadUnitProducts = `
+		SELECT PB.Prod_Code
+		FROM dbo.PU_Products AS PUP
+		JOIN dbo.Products_Base AS PB ON PB.Prod_Id = PUP.Prod_Id
+		WHERE PUP.PU_Id = @param_pu_id
+		ORDER BY PB.Prod_Code;
+		`
+
+	queryAddUnitProduct = `
+		SET XACT_ABORT ON;
+
+		DECLARE @pl_desc			NVARCHAR(50),
+				@pu_desc			NVARCHAR(50),
+				@msg				NVARCHAR(2048);
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc
+		FROM dbo.Prod_Units_Base AS PUB
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+
+		IF @pu_desc IS NULL
+			THROW 50000, 'unit not found', 1;
+
+		IF NOT EXISTS (SELECT 1 FROM dbo.Products_Base AS PB WHERE PB.Prod_Code = @param_prod_code)
+		BEGIN
+			SET @msg = N'product not found: ' + @param_prod_code;
+			THROW 50000, @msg, 1;
+		END
+
+		EXEC	@return_value = [dbo].[spEM_IEImportProductsToUnits]
+				@PL_Desc = @pl_desc,
+				@PU_Desc = @pu_desc,
+				@Prod_Code = @param_prod_code,
+				@Prod_Code_Xref = NULL,
+				@User_Id = @param_user_id,
+				@Trans_Id = NULL;
+		`
+
+	// spEM_DropUnitProduct is what the PA Administrator calls to unassign a product, so the
+	// removal is audited like one made by hand.
+	queryRemoveUnitProduct = `
+		SET XACT_ABORT ON;
+
+		DECLARE @prod_id INT = (SELECT PB.Prod_Id FROM dbo.Products_Base AS PB WHERE PB.Prod_Code = @param_prod_code);
+
+		IF @prod_id IS NULL
+			THROW 50000, 'product not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_DropUnitProduct]
+				@PU_Id = @param_pu_id,
+				@Prod_Id = @prod_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+// resourceUnitProducts owns the complete product list of one unit. Products assigned to the
+// unit outside Terraform show up as drift and are removed on the next apply.
+func resourceUnitProducts() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUnitProductsCreate,
+		ReadContext:   resourceUnitProductsRead,
+		UpdateContext: resourceUnitProductsUpdate,
+		DeleteContext: resourceUnitProductsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUnitImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"unit_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"product_codes": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// syncUnitProducts brings the unit's products in line with desired in one transaction,
+// diffing against what is assigned right now rather than against state.
+func syncUnitProducts(ctx context.Context, m interface{}, unitID int64, desired *schema.Set) error {
+	userId := 1
+
+	return withTransaction(ctx, m, func(tx *sql.Tx) error {
+		codes, err := scanProductCodes(tx.QueryContext(ctx, queryLoadUnitProducts, sql.Named("param_pu_id", unitID)))
+		if err != nil {
+			return err
+		}
+		current := schema.NewSet(schema.HashString, codes)
+
+		for _, code := range current.Difference(desired).List() {
+			var returnValue sql.NullInt64
+			_, err := tx.ExecContext(ctx, queryRemoveUnitProduct,
+				sql.Named("return_value", sql.Out{Dest: &returnValue}),
+				sql.Named("param_pu_id", unitID),
+				sql.Named("param_prod_code", code.(string)),
+				sql.Named("param_user_id", userId),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to remove product %q from unit %d: %w", code, unitID, err)
+			}
+			if returnValue.Int64 != 0 {
+				return fmt.Errorf("stored procedure returned failure status %d removing product %q", returnValue.Int64, code)
+			}
+		}
+
+		for _, code := range desired.Difference(current).List() {
+			var returnValue sql.NullInt64
+			_, err := tx.ExecContext(ctx, queryAddUnitProduct,
+				sql.Named("return_value", sql.Out{Dest: &returnValue}),
+				sql.Named("param_pu_id", unitID),
+				sql.Named("param_prod_code", code.(string)),
+				sql.Named("param_user_id", userId),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to add product %q to unit %d: %w", code, unitID, err)
+			}
+			if returnValue.Int64 != 0 {
+				return fmt.Errorf("stored procedure returned failure status %d adding product %q", returnValue.Int64, code)
+			}
+		}
+		return nil
+	})
+}
+
+func resourceUnitProductsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unitID := int64(d.Get("unit_id").(int))
+
+	if err := syncUnitProducts(ctx, m, unitID, d.Get("product_codes").(*schema.Set)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(int64ToString(unitID))
+	return resourceUnitProductsRead(ctx, d, m)
+}
+
+func resourceUnitProductsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var unitID int64
+	err = db.QueryRowContext(ctx, queryGetUnitExists, sql.Named("param_pu_id", id)).Scan(&unitID)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	codes, err := scanProductCodes(db.QueryContext(ctx, queryLoadUnitProducts, sql.Named("param_pu_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("unit_id", unitID)
+	d.Set("product_codes", codes)
+	return nil
+}
+
+func resourceUnitProductsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := syncUnitProducts(ctx, m, id, d.Get("product_codes").(*schema.Set)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceUnitProductsRead(ctx, d, m)
+}
+
+func resourceUnitProductsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := syncUnitProducts(ctx, m, id, schema.NewSet(schema.HashString, nil)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
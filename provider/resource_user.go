@@ -0,0 +1,477 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type User struct {
+	User_Id   int64
+	Username  string
+	FullName  string
+	Active    bool
+	RoleBased bool
+	Language  string
+}
+
+const (
+	// The user's language is the LanguageNumber user parameter.
+	queryGetUser = `
+		SELECT U.User_Id, U.Username, U.User_Desc, U.Active, U.Role_Based_Security, L.Language_Name
+		FROM dbo.Users AS U
+		LEFT JOIN dbo.User_Parameters AS UP ON UP.User_Id = U.User_Id
+			AND UP.Parm_Id = (SELECT P.Parm_Id FROM dbo.Parameters AS P WHERE P.Parm_Name = 'LanguageNumber')
+		LEFT JOIN dbo.Languages AS L ON CAST(L.Language_Id AS NVARCHAR(25)) = UP.Value
+		WHERE U.User_Id = @param_user_id
+		AND COALESCE(U.Is_Role, 0) = 0;
+		`
+
+	queryGetUserForUpdate = `
+		SELECT U.User_Id, U.Username, U.User_Desc, U.Active, U.Role_Based_Security, L.Language_Name
+		FROM dbo.Users AS U WITH (UPDLOCK, HOLDLOCK)
+		LEFT JOIN dbo.User_Parameters AS UP ON UP.User_Id = U.User_Id
+			AND UP.Parm_Id = (SELECT P.Parm_Id FROM dbo.Parameters AS P WHERE P.Parm_Name = 'LanguageNumber')
+		LEFT JOIN dbo.Languages AS L ON CAST(L.Language_Id AS NVARCHAR(25)) = UP.Value
+		WHERE U.User_Id = @param_user_id
+		AND COALESCE(U.Is_Role, 0) = 0;
+		`
+
+	queryGetUserIdByName = `
+		SELECT U.User_Id
+		FROM dbo.Users AS U
+		WHERE U.Username = @param_username
+		AND COALESCE(U.Is_Role, 0) = 0;
+		`
+
+	queryCreateUser = `
+		EXEC	@return_value = [dbo].[spEM_IEImportUsers]
+				@UserName = @param_username,
+				@User_Desc = @param_full_name,
+				@Password = @param_password,
+				@Active_Desc = @param_active,
+				@View_Desc = NULL,
+				@WindowsLoginInfo = NULL,
+				@RoleBased_Desc = @param_role_based,
+				@MixedMode_Desc = NULL,
+				@SSOName = NULL,
+				@UseSSO = NULL,
+				@In_User_Id = @param_in_user_id;
+		`
+
+	queryUpdateUser = `
+		UPDATE dbo.Users SET
+			Username = @param_username,
+			User_Desc = @param_full_name,
+			Active = @param_active,
+			Role_Based_Security = @param_role_based
+		WHERE User_Id = @param_user_id
+		AND COALESCE(Is_Role, 0) = 0;
+		`
+
+	// An empty language removes the parameter so the site default applies.
+	querySetUserLanguage = `
+		SET XACT_ABORT ON;
+
+		DECLARE @parm_id INT, @language_id INT;
+
+		SET @parm_id = (SELECT P.Parm_Id FROM dbo.Parameters AS P WHERE P.Parm_Name = 'LanguageNumber');
+
+		IF @param_language IS NOT NULL
+		BEGIN
+			SET @language_id = (SELECT L.Language_Id FROM dbo.Languages AS L WHERE L.Language_Name = @param_language);
+
+			IF @language_id IS NULL
+				THROW 50000, 'language not found', 1;
+		END
+
+		DELETE FROM dbo.User_Parameters WHERE User_Id = @param_user_id AND Parm_Id = @parm_id;
+
+		IF @language_id IS NOT NULL
+			INSERT INTO dbo.User_Parameters (User_Id, Parm_Id, HostName, Value)
+			VALUES (@param_user_id, @parm_id, '', CAST(@language_id AS NVARCHAR(25)));
+		`
+
+	queryLoadUserRoles = `
+		SELECT URS.Role_User_Id
+		FROM dbo.User_Role_Security AS URS
+		WHERE URS.User_Id = @param_user_id
+		ORDER BY URS.Role_User_Id;
+		`
+
+	// spEM_IEImportSecurityRoles adds one member to an existing role.
+	queryAddUserRole = `
+		SET XACT_ABORT ON;
+
+		DECLARE @role_name			NVARCHAR(100);
+
+		SET @role_name = (
+			SELECT U.Username FROM dbo.Users AS U WHERE U.User_Id = @param_role_id AND U.Is_Role = 1);
+
+		IF @role_name IS NULL
+			THROW 50000, 'security role not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportSecurityRoles]
+				@SecurityRole = @role_name,
+				@SecurityRoleMember = @param_username,
+				@SecurityGroupDesc = NULL,
+				@ALDesc = NULL,
+				@IsNTGroup = '0',
+				@DomainName = NULL,
+				@InUserId = @param_in_user_id;
+		`
+
+	// spEM_IEImportSecurityRoles only ever adds a member and PA has no procedure that removes
+	// one, so leaving a role deletes the User_Role_Security row directly, as the PA
+	// Administrator does.
+	queryRemoveUserRole = `
+		DELETE FROM dbo.User_Role_Security
+		WHERE Role_User_Id = @param_role_id
+		AND User_Id = @param_user_id;
+		`
+
+	// Users are referenced by every audit trail, so destroying one deactivates it and removes
+	// its role memberships and group grants instead of deleting the row. PA has no procedure
+	// that drops a user, revokes a grant or removes a role member, so all three are written
+	// directly.
+	queryDeactivateUser = `
+		SET XACT_ABORT ON;
+
+		DELETE FROM dbo.User_Role_Security WHERE User_Id = @param_user_id;
+		DELETE FROM dbo.User_Security WHERE User_Id = @param_user_id;
+		UPDATE dbo.Users SET Active = 0 WHERE User_Id = @param_user_id AND COALESCE(Is_Role, 0) = 0;
+		`
+)
+
+func resourceUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceUserImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"username": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"full_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "User_Desc.",
+				ValidateFunc: validation.StringLenBetween(0, 100),
+			},
+			"initial_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Only used when the user is created; later changes are ignored.",
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Id() != ""
+				},
+				ValidateFunc: validation.StringLenBetween(0, 100),
+			},
+			"active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"role_based_security": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the user's access comes from its roles rather than its own group grants.",
+			},
+			"language": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Language_Name from dbo.Languages. Unset uses the site default.",
+			},
+			"role_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Every security role the user is a member of. Memberships added outside Terraform are removed.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Set:         schema.HashInt,
+			},
+		},
+	}
+}
+
+func scanUser(row interface{ Scan(...interface{}) error }) (*User, error) {
+	var user User
+	var username, fullName, language sql.NullString
+	var active, roleBased sql.NullBool
+	if err := row.Scan(&user.User_Id, &username, &fullName, &active, &roleBased, &language); err != nil {
+		return nil, err
+	}
+	user.Username = nullableStringToString(username)
+	user.FullName = nullableStringToString(fullName)
+	user.Active = active.Bool
+	user.RoleBased = roleBased.Bool
+	user.Language = nullableStringToString(language)
+	return &user, nil
+}
+
+func loadUserRoles(rows *sql.Rows, err error) ([]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []interface{}
+	for rows.Next() {
+		var roleID int64
+		if err := rows.Scan(&roleID); err != nil {
+			return nil, err
+		}
+		roles = append(roles, int(roleID))
+	}
+	return roles, rows.Err()
+}
+
+func execSetUserLanguage(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, userID int64) error {
+	_, err := tx.ExecContext(ctx, querySetUserLanguage,
+		sql.Named("param_user_id", userID),
+		sql.Named("param_language", stringToNullString(d.Get("language").(string))),
+	)
+	return err
+}
+
+// syncUserRoles adds and removes role memberships so the user matches desired exactly.
+func syncUserRoles(ctx context.Context, tx *sql.Tx, userID int64, username string, desired *schema.Set) error {
+	userId := 1
+
+	roles, err := loadUserRoles(tx.QueryContext(ctx, queryLoadUserRoles, sql.Named("param_user_id", userID)))
+	if err != nil {
+		return err
+	}
+	current := schema.NewSet(schema.HashInt, roles)
+
+	for _, roleID := range current.Difference(desired).List() {
+		_, err := tx.ExecContext(ctx, queryRemoveUserRole,
+			sql.Named("param_role_id", int64(roleID.(int))),
+			sql.Named("param_user_id", userID),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to remove user %q from role %d: %w", username, roleID, err)
+		}
+	}
+
+	for _, roleID := range desired.Difference(current).List() {
+		var returnValue sql.NullInt64
+		_, err := tx.ExecContext(ctx, queryAddUserRole,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_role_id", int64(roleID.(int))),
+			sql.Named("param_username", username),
+			sql.Named("param_in_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add user %q to role %d: %w", username, roleID, err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status %d adding user to role %d", returnValue.Int64, roleID)
+		}
+	}
+	return nil
+}
+
+func execUpdateUser(ctx context.Context, tx *sql.Tx, d *schema.ResourceData, userID int64) error {
+	_, err := tx.ExecContext(ctx, queryUpdateUser,
+		sql.Named("param_user_id", userID),
+		sql.Named("param_username", d.Get("username").(string)),
+		sql.Named("param_full_name", stringToNullString(d.Get("full_name").(string))),
+		sql.Named("param_active", boolToInt64(d.Get("active").(bool))),
+		sql.Named("param_role_based", boolToInt64(d.Get("role_based_security").(bool))),
+	)
+	return err
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+	username := d.Get("username").(string)
+
+	var userID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetUserIdByName, sql.Named("param_username", username)).Scan(&userID)
+		if err == nil {
+			existing, err := scanUser(tx.QueryRowContext(ctx, queryGetUserForUpdate, sql.Named("param_user_id", userID)))
+			if err != nil {
+				return err
+			}
+			if existing.Active {
+				return fmt.Errorf("user %q already exists; import it instead", username)
+			}
+			// A destroyed pa_user is only deactivated, so re-creating it takes the row back.
+			// The existing password is kept; initial_password only applies to new users.
+			if err := execUpdateUser(ctx, tx, d, userID); err != nil {
+				return fmt.Errorf("failed to reactivate user %q: %w", username, err)
+			}
+			if err := execSetUserLanguage(ctx, tx, d, userID); err != nil {
+				return err
+			}
+			return syncUserRoles(ctx, tx, userID, username, d.Get("role_ids").(*schema.Set))
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		var returnValue sql.NullInt64
+		_, err = tx.ExecContext(ctx, queryCreateUser,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_username", username),
+			sql.Named("param_full_name", stringToNullString(d.Get("full_name").(string))),
+			sql.Named("param_password", stringToNullString(d.Get("initial_password").(string))),
+			sql.Named("param_active", int64ToString(boolToInt64(d.Get("active").(bool)))),
+			sql.Named("param_role_based", int64ToString(boolToInt64(d.Get("role_based_security").(bool)))),
+			sql.Named("param_in_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetUserIdByName, sql.Named("param_username", username)).Scan(&userID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user %q was not created", username)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := execSetUserLanguage(ctx, tx, d, userID); err != nil {
+			return err
+		}
+		return syncUserRoles(ctx, tx, userID, username, d.Get("role_ids").(*schema.Set))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("user_id", int(userID))
+	d.SetId(int64ToString(userID))
+	return resourceUserRead(ctx, d, m)
+}
+
+func resourceUserRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	user, err := scanUser(db.QueryRowContext(ctx, queryGetUser, sql.Named("param_user_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	roles, err := loadUserRoles(db.QueryContext(ctx, queryLoadUserRoles, sql.Named("param_user_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("user_id", user.User_Id)
+	d.Set("username", user.Username)
+	d.Set("full_name", user.FullName)
+	d.Set("active", user.Active)
+	d.Set("role_based_security", user.RoleBased)
+	d.Set("language", user.Language)
+	d.Set("role_ids", roles)
+	return nil
+}
+
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	username := d.Get("username").(string)
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanUser(tx.QueryRowContext(ctx, queryGetUserForUpdate, sql.Named("param_user_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("user %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "user", map[string]interface{}{
+			"username":            current.Username,
+			"full_name":           current.FullName,
+			"active":              current.Active,
+			"role_based_security": current.RoleBased,
+			"language":            current.Language,
+		}); err != nil {
+			return err
+		}
+
+		if err := execUpdateUser(ctx, tx, d, id); err != nil {
+			return err
+		}
+		if d.HasChange("language") {
+			if err := execSetUserLanguage(ctx, tx, d, id); err != nil {
+				return err
+			}
+		}
+		return syncUserRoles(ctx, tx, id, username, d.Get("role_ids").(*schema.Set))
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceUserRead(ctx, d, m)
+}
+
+func resourceUserDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeactivateUser, sql.Named("param_user_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceUserImport accepts the username.
+func resourceUserImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetUserIdByName,
+		sql.Named("param_username", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	// Grants to security roles belong to pa_security_role and are left alone here.
+	queryLoadUserGroupMembers = `
+		SELECT US.User_Id, US.Access_Level
+		FROM dbo.User_Security AS US
+		JOIN dbo.Users AS U ON U.User_Id = US.User_Id
+		WHERE US.Group_Id = @param_group_id
+		AND COALESCE(U.Is_Role, 0) = 0
+		ORDER BY US.User_Id;
+		`
+
+	// spEM_IEImportUserGroups grants one user access to a security group.
+	queryAddUserGroupMember = `
+		SET XACT_ABORT ON;
+
+		DECLARE @group_desc			NVARCHAR(100),
+				@username			NVARCHAR(100),
+				@al_desc			NVARCHAR(100);
+
+		SET @group_desc = (
+			SELECT SG.Group_Desc FROM dbo.Security_Groups AS SG WHERE SG.Group_Id = @param_group_id);
+
+		IF @group_desc IS NULL
+			THROW 50000, 'security group not found', 1;
+
+		SET @username = (
+			SELECT U.Username FROM dbo.Users AS U WHERE U.User_Id = @param_member_id AND COALESCE(U.Is_Role, 0) = 0);
+
+		IF @username IS NULL
+			THROW 50000, 'user not found', 1;
+
+		SET @al_desc = (
+			SELECT AL.AL_Desc FROM dbo.Access_Level AS AL WHERE AL.AL_Id = @param_al_id);
+
+		IF @al_desc IS NULL
+			THROW 50000, 'access level not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportUserGroups]
+				@Group_Desc = @group_desc,
+				@UserName = @username,
+				@AL_Desc = @al_desc,
+				@In_User_Id = @param_user_id;
+		`
+
+	queryRemoveUserGroupMember = `
+		DELETE FROM dbo.User_Security
+		WHERE Group_Id = @param_group_id
+		AND User_Id = @param_member_id;
+		`
+)
+
+// resourceUserGroup owns the users granted access to one security group.
+func resourceUserGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserGroupCreate,
+		ReadContext:   resourceUserGroupRead,
+		UpdateContext: resourceUserGroupUpdate,
+		DeleteContext: resourceUserGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceSecurityGroupImport,
+		},
+		CustomizeDiff: resourceUserGroupCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"security_group_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"members": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Every user granted the group. Grants added outside Terraform are removed.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_id": {
+							Type:     schema.TypeInt,
+							Required: true,
+						},
+						"access_level": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(accessLevels, false),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceUserGroupCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	seen := map[int]bool{}
+	for _, v := range d.Get("members").(*schema.Set).List() {
+		userID := v.(map[string]interface{})["user_id"].(int)
+		if userID != 0 && seen[userID] {
+			return fmt.Errorf("user %d has more than one members block", userID)
+		}
+		seen[userID] = true
+	}
+	return nil
+}
+
+// syncUserGroupMembers grants and revokes so the group's users match desired exactly. A
+// changed level is revoked and granted again.
+func syncUserGroupMembers(ctx context.Context, m interface{}, groupID int64, desired []interface{}) error {
+	userId := 1
+
+	return withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := loadAccessLevels(tx.QueryContext(ctx, queryLoadUserGroupMembers, sql.Named("param_group_id", groupID)))
+		if err != nil {
+			return err
+		}
+
+		wanted := map[int]string{}
+		for _, v := range desired {
+			block := v.(map[string]interface{})
+			wanted[block["user_id"].(int)] = block["access_level"].(string)
+		}
+
+		for memberID, level := range current {
+			if wanted[memberID] == level {
+				continue
+			}
+			_, err := tx.ExecContext(ctx, queryRemoveUserGroupMember,
+				sql.Named("param_group_id", groupID),
+				sql.Named("param_member_id", int64(memberID)),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to revoke security group %d from user %d: %w", groupID, memberID, err)
+			}
+		}
+
+		for memberID, level := range wanted {
+			if current[memberID] == level {
+				continue
+			}
+			var returnValue sql.NullInt64
+			_, err := tx.ExecContext(ctx, queryAddUserGroupMember,
+				sql.Named("return_value", sql.Out{Dest: &returnValue}),
+				sql.Named("param_group_id", groupID),
+				sql.Named("param_member_id", int64(memberID)),
+				sql.Named("param_al_id", accessLevelId(level)),
+				sql.Named("param_user_id", userId),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to grant security group %d to user %d: %w", groupID, memberID, err)
+			}
+			if returnValue.Int64 != 0 {
+				return fmt.Errorf("stored procedure returned failure status %d granting user %d", returnValue.Int64, memberID)
+			}
+		}
+		return nil
+	})
+}
+
+func resourceUserGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	groupID := int64(d.Get("security_group_id").(int))
+
+	if err := syncUserGroupMembers(ctx, m, groupID, d.Get("members").(*schema.Set).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(int64ToString(groupID))
+	return resourceUserGroupRead(ctx, d, m)
+}
+
+func resourceUserGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := scanSecurityGroup(db.QueryRowContext(ctx, queryGetSecurityGroup, sql.Named("param_group_id", id))); err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	} else if err != nil {
+		return diag.FromErr(err)
+	}
+
+	current, err := loadAccessLevels(db.QueryContext(ctx, queryLoadUserGroupMembers, sql.Named("param_group_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	members := make([]interface{}, 0, len(current))
+	for memberID, level := range current {
+		members = append(members, map[string]interface{}{
+			"user_id":      memberID,
+			"access_level": level,
+		})
+	}
+
+	d.Set("security_group_id", id)
+	if err := d.Set("members", members); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceUserGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := syncUserGroupMembers(ctx, m, id, d.Get("members").(*schema.Set).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceUserGroupRead(ctx, d, m)
+}
+
+func resourceUserGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := syncUserGroupMembers(ctx, m, id, nil); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
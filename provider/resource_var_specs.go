@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetVariableExists = `
+		SELECT VB.Var_Id
+		FROM dbo.Variables_Base AS VB
+		WHERE VB.Var_Id = @param_var_id;
+		`
+
+	// Only active specifications count, and a product whose limits were all cleared is
+	// treated as having none.
+	queryLoadVarSpecs = `
+		SELECT	VS.Prod_Id, VS.L_Entry, VS.L_Reject, VS.L_Warning, VS.L_User, VS.Target,
+				VS.U_User, VS.U_Warning, VS.U_Reject, VS.U_Entry,
+				VS.L_Control, VS.T_Control, VS.U_Control, VS.Test_Freq, VS.Esignature_Level
+		FROM dbo.Var_Specs AS VS
+		WHERE VS.Var_Id = @param_var_id
+		AND VS.Expiration_Date IS NULL
+		AND COALESCE(VS.L_Entry, VS.L_Reject, VS.L_Warning, VS.L_User, VS.Target,
+				VS.U_User, VS.U_Warning, VS.U_Reject, VS.U_Entry,
+				VS.L_Control, VS.T_Control, VS.U_Control,
+				CAST(VS.Test_Freq AS VARCHAR(25)), CAST(VS.Esignature_Level AS VARCHAR(25))) IS NOT NULL
+		ORDER BY VS.Prod_Id;
+		`
+
+	// spEM_IEImportVarSpecs raises a Trans_Variables transaction for one variable and product.
+	// Passing every limit as NULL clears the product's specification.
+	queryImportVarSpec = `
+		SET XACT_ABORT ON;
+
+		DECLARE @pl_desc			NVARCHAR(50),
+				@pu_desc			NVARCHAR(50),
+				@var_desc			NVARCHAR(50),
+				@prod_desc			NVARCHAR(50);
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc, @var_desc = VB.Var_Desc
+		FROM dbo.Variables_Base AS VB
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE VB.Var_Id = @param_var_id;
+
+		IF @var_desc IS NULL
+			THROW 50000, 'variable not found', 1;
+
+		SET @prod_desc = (
+			SELECT PB.Prod_Desc FROM dbo.Products_Base AS PB WHERE PB.Prod_Id = @param_prod_id);
+
+		IF @prod_desc IS NULL
+			THROW 50000, 'product not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportVarSpecs]
+				@PL_Desc = @pl_desc,
+				@PU_Desc = @pu_desc,
+				@Var_Desc = @var_desc,
+				@Prod_Desc = @prod_desc,
+				@L_Entry = @p_l_entry,
+				@L_Reject = @p_l_reject,
+				@L_Warning = @p_l_warning,
+				@L_User = @p_l_user,
+				@Target = @p_target,
+				@U_User = @p_u_user,
+				@U_Warning = @p_u_warning,
+				@U_Reject = @p_u_reject,
+				@U_Entry = @p_u_entry,
+				@Test_Freq_Str = @p_test_frequency,
+				@ESigLevel = @p_esignature_level,
+				@L_Control = @p_l_control,
+				@T_Control = @p_t_control,
+				@U_Control = @p_u_control,
+				@OL_Entry = NULL,
+				@OU_User = NULL,
+				@OU_Warning = NULL,
+				@OU_Reject = NULL,
+				@OU_Entry = NULL,
+				@OTest_Freq_Str = NULL,
+				@OESigLevel = NULL,
+				@OL_Control = NULL,
+				@OT_Control = NULL,
+				@OU_Control = NULL,
+				@User_Id = @param_user_id,
+				@Trans_Id = NULL;
+		`
+)
+
+// resourceVarSpecs owns the active specification limits of one variable, one block per
+// product. Products with limits that are not configured are cleared on apply.
+func resourceVarSpecs() *schema.Resource {
+	limits := specLimitsSchema()
+	limits["product_id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Required: true,
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceVarSpecsCreate,
+		ReadContext:   resourceVarSpecsRead,
+		UpdateContext: resourceVarSpecsUpdate,
+		DeleteContext: resourceVarSpecsDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVariableImport,
+		},
+		CustomizeDiff: resourceVarSpecsCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"variable_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"limits": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Resource{Schema: limits},
+			},
+		},
+	}
+}
+
+func resourceVarSpecsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	seen := map[int]bool{}
+	for _, v := range d.Get("limits").(*schema.Set).List() {
+		block := v.(map[string]interface{})
+		prodID := block["product_id"].(int)
+		if prodID != 0 && seen[prodID] {
+			return fmt.Errorf("product %d has more than one limits block", prodID)
+		}
+		seen[prodID] = true
+		if err := validateSpecLimitOrder(fmt.Sprintf("limits for product %d", prodID), block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadVarSpecs returns the active limits keyed by product, in the shape of a limits block.
+func loadVarSpecs(rows *sql.Rows, err error) (map[int]map[string]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	specs := map[int]map[string]interface{}{}
+	for rows.Next() {
+		var prodID int64
+		block, err := scanSpecLimits(rows, &prodID)
+		if err != nil {
+			return nil, err
+		}
+		block["product_id"] = int(prodID)
+		specs[int(prodID)] = block
+	}
+	return specs, rows.Err()
+}
+
+func execImportVarSpec(ctx context.Context, tx *sql.Tx, varID int64, prodID int, block map[string]interface{}) error {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	args := append(specLimitArgs(block),
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_var_id", varID),
+		sql.Named("param_prod_id", int64(prodID)),
+		sql.Named("param_user_id", userId),
+	)
+
+	if _, err := tx.ExecContext(ctx, queryImportVarSpec, args...); err != nil {
+		return fmt.Errorf("failed to set limits for product %d: %w", prodID, err)
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status %d setting limits for product %d", returnValue.Int64, prodID)
+	}
+	return nil
+}
+
+// syncVarSpecs writes only the products whose limits differ from what is active right now,
+// so each apply raises as few transactions as possible.
+func syncVarSpecs(ctx context.Context, m interface{}, varID int64, desired []interface{}) error {
+	return withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := loadVarSpecs(tx.QueryContext(ctx, queryLoadVarSpecs, sql.Named("param_var_id", varID)))
+		if err != nil {
+			return err
+		}
+
+		wanted := map[int]bool{}
+		for _, v := range desired {
+			block := v.(map[string]interface{})
+			prodID := block["product_id"].(int)
+			wanted[prodID] = true
+			if existing, ok := current[prodID]; ok && sameSpecLimits(existing, block) {
+				continue
+			}
+			if err := execImportVarSpec(ctx, tx, varID, prodID, block); err != nil {
+				return err
+			}
+		}
+
+		for prodID := range current {
+			if wanted[prodID] {
+				continue
+			}
+			if err := execImportVarSpec(ctx, tx, varID, prodID, map[string]interface{}{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func resourceVarSpecsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	varID := int64(d.Get("variable_id").(int))
+
+	if err := syncVarSpecs(ctx, m, varID, d.Get("limits").(*schema.Set).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(int64ToString(varID))
+	return resourceVarSpecsRead(ctx, d, m)
+}
+
+func resourceVarSpecsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var varID int64
+	err = db.QueryRowContext(ctx, queryGetVariableExists, sql.Named("param_var_id", id)).Scan(&varID)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	specs, err := loadVarSpecs(db.QueryContext(ctx, queryLoadVarSpecs, sql.Named("param_var_id", id)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	limits := make([]interface{}, 0, len(specs))
+	for _, block := range specs {
+		limits = append(limits, block)
+	}
+
+	d.Set("variable_id", varID)
+	if err := d.Set("limits", limits); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourceVarSpecsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := syncVarSpecs(ctx, m, id, d.Get("limits").(*schema.Set).List()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceVarSpecsRead(ctx, d, m)
+}
+
+func resourceVarSpecsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := syncVarSpecs(ctx, m, id, nil); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,755 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+// variableField ties one pa_variable attribute to its spEM_IEImportVariables parameter and to
+// the expression queryGetVariable reads it back with. The procedure takes every value as a
+// string and does the description-to-ID lookups itself. Fields without a Column can't be
+// read back (the procedure folds them into another column) and are kept as configured.
+type variableField struct {
+	Attribute string
+	Param     string
+	Column    string
+	Schema    *schema.Schema
+}
+
+func variableString(validate schema.SchemaValidateFunc) *schema.Schema {
+	return &schema.Schema{Type: schema.TypeString, Optional: true, ValidateFunc: validate}
+}
+
+func variableLookup(description string) *schema.Schema {
+	return &schema.Schema{Type: schema.TypeString, Optional: true, Computed: true, Description: description}
+}
+
+func variableInt() *schema.Schema {
+	return &schema.Schema{Type: schema.TypeInt, Optional: true, Computed: true}
+}
+
+func variableFloat() *schema.Schema {
+	return &schema.Schema{Type: schema.TypeFloat, Optional: true, Computed: true}
+}
+
+func variableBool() *schema.Schema {
+	return &schema.Schema{Type: schema.TypeBool, Optional: true, Default: false}
+}
+
+var variableFields = []variableField{
+	{"data_source", "DS_Desc", "DS.DS_Desc", &schema.Schema{Type: schema.TypeString, Required: true, Description: "DS_Desc from dbo.Data_Source."}},
+	{"write_group_data_source", "Write_Group_DS_Desc", "WDS.DS_Desc", variableLookup("DS_Desc from dbo.Data_Source.")},
+	{"event_type", "ET_Desc", "ET.ET_Desc", &schema.Schema{Type: schema.TypeString, Required: true, Description: "ET_Desc from dbo.Event_Types."}},
+	{"event_subtype", "EventSubtype", "ES.Event_Subtype_Desc", variableLookup("Event_Subtype_Desc from dbo.Event_Subtypes.")},
+	{"event_dimension", "EventDimension", "ED.ED_Desc", variableLookup("ED_Desc from dbo.Event_Dimensions.")},
+	{"input_name", "InputName", "PEI.Input_Name", variableLookup("Input_Name from dbo.PrdExec_Inputs.")},
+	{"data_type", "Data_Type_Desc", "DT.Data_Type_Desc", &schema.Schema{Type: schema.TypeString, Required: true, Description: "Data_Type_Desc from dbo.Data_Type."}},
+	{"precision", "Var_Precision", "VB.Var_Precision", variableInt()},
+	{"eng_units", "Eng_Units", "VB.Eng_Units", variableString(validation.StringLenBetween(0, 15))},
+	{"sampling_interval", "Sampling_Interval", "VB.Sampling_Interval", variableInt()},
+	{"sampling_offset", "Sampling_Offset", "VB.Sampling_Offset", variableInt()},
+	{"sampling_type", "ST_Desc", "ST.ST_Desc", variableLookup("ST_Desc from dbo.Sampling_Type.")},
+	{"sampling_window", "Sampling_Window", "VB.Sampling_Window", variableInt()},
+	{"sampling_window_type", "Sampling_Window_Type", "", variableString(nil)},
+	{"spec_activation", "SA_Desc", "SA.SA_Desc", variableLookup("SA_Desc from dbo.Spec_Activations.")},
+	{"specification", "Spec_Desc", "PP.Prop_Desc + ' / ' + S.Spec_Desc", &schema.Schema{Type: schema.TypeString, Optional: true, Computed: true, ValidateFunc: validateSpecPath(), Description: "\"Property / Spec\"; or set specification_id."}},
+	{"input_tag", "Input_Tag", "VB.Input_Tag", variableString(validateVarchar255())},
+	{"input_tag2", "Input_Tag2", "VB.Input_Tag2", variableString(validateVarchar255())},
+	{"output_tag", "Output_Tag", "VB.Output_Tag", variableString(validateVarchar255())},
+	{"dq_tag", "DQ_Tag", "VB.DQ_Tag", variableString(validateVarchar255())},
+	{"uel_tag", "UEL_Tag", "VB.UEL_Tag", variableString(validateVarchar255())},
+	{"url_tag", "URL_Tag", "VB.URL_Tag", variableString(validateVarchar255())},
+	{"uwl_tag", "UWL_Tag", "VB.UWL_Tag", variableString(validateVarchar255())},
+	{"uul_tag", "UUL_Tag", "VB.UUL_Tag", variableString(validateVarchar255())},
+	{"target_tag", "Target_Tag", "VB.Target_Tag", variableString(validateVarchar255())},
+	{"lul_tag", "LUL_Tag", "VB.LUL_Tag", variableString(validateVarchar255())},
+	{"lwl_tag", "LWL_Tag", "VB.LWL_Tag", variableString(validateVarchar255())},
+	{"lrl_tag", "LRL_Tag", "VB.LRL_Tag", variableString(validateVarchar255())},
+	{"lel_tag", "LEL_Tag", "VB.LEL_Tag", variableString(validateVarchar255())},
+	{"dq_type", "DQ_Type", "CO.Comparison_Operator_Value", variableLookup("Comparison_Operator_Value from dbo.Comparison_Operators.")},
+	{"dq_value", "DQ_Value", "VB.Comparison_Value", variableString(validateVarchar50())},
+	{"extended_info", "Extended_Info", "VB.Extended_Info", variableString(validateVarchar255())},
+	{"external_link", "External_Link", "VB.External_Link", variableString(validateVarchar255())},
+	{"user_defined1", "User_Defined1", "VB.User_Defined1", variableString(validateVarchar255())},
+	{"user_defined2", "User_Defined2", "VB.User_Defined2", variableString(validateVarchar255())},
+	{"user_defined3", "User_Defined3", "VB.User_Defined3", variableString(validateVarchar255())},
+	{"alias", "VarAlias", "VB.Test_Name", variableString(validateVarchar50())},
+	{"tot_factor", "Tot_Factor", "VB.Tot_Factor", variableFloat()},
+	{"repeating", "Repeating", "VB.Repeating", variableBool()},
+	{"repeat_backtime", "BackTime", "VB.Repeat_Backtime", variableInt()},
+	{"test_frequency", "DF_TestFreq", "", variableInt()},
+	{"tf_reset", "TF_Reset", "VB.TF_Reset", variableBool()},
+	{"extended_test_freq", "Extended_Test_Freq", "ETF.Extended_Test_Freq", variableLookup("Extended_Test_Freq from dbo.Extended_Test_Freqs.")},
+	{"should_archive", "ShouldArchive", "VB.ShouldArchive", variableBool()},
+	{"max_rpm", "MaxRPM", "VB.Max_RPM", variableFloat()},
+	{"reset_value", "ResetValue", "VB.Reset_Value", variableFloat()},
+	{"conformance_variable", "Conformance_Variable", "VB.Is_Conformance_Variable", variableBool()},
+	{"esignature_level", "Esignature_Level", "VB.Esignature_Level", variableInt()},
+	{"spc_calculation_type", "SPCCalculationType", "SCT.SPC_Calculation_Type_Desc", variableLookup("SPC_Calculation_Type_Desc from dbo.SPC_Calculation_Types.")},
+	{"spc_group_variable_type", "SPCGroupVariableType", "SGVT.SPC_Group_Variable_Type_Desc", variableLookup("SPC_Group_Variable_Type_Desc from dbo.SPC_Group_Variable_Types.")},
+	// The procedure stores SignEntry in ArrayStatOnly and ArrayStat in Force_Sign_Entry.
+	{"sign_entry", "SignEntry", "VB.ArrayStatOnly", variableBool()},
+	{"array_stat", "ArrayStat", "VB.Force_Sign_Entry", variableInt()},
+	{"rank", "sRank", "VB.Rank", variableInt()},
+	{"unit_reject", "UnitReject", "VB.Unit_Reject", variableBool()},
+	{"unit_summarize", "UnitSummarize", "VB.Unit_Summarize", variableBool()},
+	{"var_reject", "VarReject", "VB.Var_Reject", variableBool()},
+	{"cpk_subgroup_size", "sCPKSubGroup", "VB.CPK_SubGroup_Size", variableInt()},
+	{"string_spec_setting", "StringSpecSetting", "VB.String_Specification_Setting", variableInt()},
+	{"read_lag_time", "sReadLagtime", "VB.ReadLagTime", variableInt()},
+	{"perform_lookup", "sPerformLookup", "VB.Perform_Event_Lookup", variableBool()},
+	{"ignore_event_status", "sIgnoreStatus", "VB.Ignore_Event_Status", variableBool()},
+	{"product_code", "ProductCode", "", variableString(nil)},
+}
+
+// variableLookups are checked before calling the procedure, which otherwise silently
+// stores NULL for a description it can't resolve.
+var variableLookups = []struct {
+	Param string
+	Query string
+}{
+	{"DS_Desc", "SELECT 1 FROM dbo.Data_Source WHERE DS_Desc = @p_DS_Desc"},
+	{"Write_Group_DS_Desc", "SELECT 1 FROM dbo.Data_Source WHERE DS_Desc = @p_Write_Group_DS_Desc"},
+	{"ET_Desc", "SELECT 1 FROM dbo.Event_Types WHERE ET_Desc = @p_ET_Desc"},
+	{"Data_Type_Desc", "SELECT 1 FROM dbo.Data_Type WHERE Data_Type_Desc = @p_Data_Type_Desc"},
+	{"ST_Desc", "SELECT 1 FROM dbo.Sampling_Type WHERE ST_Desc = @p_ST_Desc"},
+	{"SA_Desc", "SELECT 1 FROM dbo.Spec_Activations WHERE SA_Desc = @p_SA_Desc"},
+	{"DQ_Type", "SELECT 1 FROM dbo.Comparison_Operators WHERE Comparison_Operator_Value = @p_DQ_Type"},
+	{"EventSubtype", "SELECT 1 FROM dbo.Event_Subtypes WHERE Event_Subtype_Desc = @p_EventSubtype"},
+	{"EventDimension", "SELECT 1 FROM dbo.Event_Dimensions WHERE ED_Desc = @p_EventDimension"},
+	{"Extended_Test_Freq", "SELECT 1 FROM dbo.Extended_Test_Freqs WHERE Extended_Test_Freq = @p_Extended_Test_Freq"},
+	{"SPCCalculationType", "SELECT 1 FROM dbo.SPC_Calculation_Types WHERE SPC_Calculation_Type_Desc = @p_SPCCalculationType"},
+	{"SPCGroupVariableType", "SELECT 1 FROM dbo.SPC_Group_Variable_Types WHERE SPC_Group_Variable_Type_Desc = @p_SPCGroupVariableType"},
+	{"Spec_Desc", "SELECT 1 FROM dbo.Specifications AS S JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = S.Prop_Id WHERE PP.Prop_Desc + ' / ' + S.Spec_Desc = @p_Spec_Desc"},
+}
+
+const (
+	// Notes from the procedure sheet that the procedure itself doesn't enforce; they are
+	// checked at plan time by resourceVariableCustomizeDiff.
+	queryIsRPMSamplingType = `
+		SELECT 1 FROM dbo.Sampling_Type WHERE ST_Desc = @param_st_desc AND ST_Id = 16;
+		`
+
+	queryIsPrecisionlessDataType = `
+		SELECT 1 FROM dbo.Data_Type WHERE Data_Type_Desc = @param_data_type_desc AND Data_Type_Id IN (2, 7);
+		`
+
+	queryGetUnitGroupUnit = `
+		SELECT PUG.PU_Id FROM dbo.PU_Groups AS PUG WHERE PUG.PUG_Id = @param_pug_id;
+		`
+
+	queryGetVariableFrom = `
+		FROM dbo.Variables_Base AS VB
+		LEFT JOIN dbo.Data_Source AS DS ON DS.DS_Id = VB.DS_Id
+		LEFT JOIN dbo.Data_Source AS WDS ON WDS.DS_Id = VB.Write_Group_DS_Id
+		LEFT JOIN dbo.Event_Types AS ET ON ET.ET_Id = VB.Event_Type
+		LEFT JOIN dbo.Event_Subtypes AS ES ON ES.Event_Subtype_Id = VB.Event_Subtype_Id
+		LEFT JOIN dbo.Event_Dimensions AS ED ON ED.ED_Id = VB.Event_Dimension
+		LEFT JOIN dbo.PrdExec_Inputs AS PEI ON PEI.PEI_Id = VB.PEI_Id
+		LEFT JOIN dbo.Data_Type AS DT ON DT.Data_Type_Id = VB.Data_Type_Id
+		LEFT JOIN dbo.Sampling_Type AS ST ON ST.ST_Id = VB.Sampling_Type
+		LEFT JOIN dbo.Spec_Activations AS SA ON SA.SA_Id = VB.SA_Id
+		LEFT JOIN dbo.Specifications AS S ON S.Spec_Id = VB.Spec_Id
+		LEFT JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = S.Prop_Id
+		LEFT JOIN dbo.Comparison_Operators AS CO ON CO.Comparison_Operator_Id = VB.Comparison_Operator_Id
+		LEFT JOIN dbo.Extended_Test_Freqs AS ETF ON ETF.Ext_Test_Freq_Id = VB.Extended_Test_Freq
+		LEFT JOIN dbo.SPC_Calculation_Types AS SCT ON SCT.SPC_Calculation_Type_Id = VB.SPC_Calculation_Type_Id
+		LEFT JOIN dbo.SPC_Group_Variable_Types AS SGVT ON SGVT.SPC_Group_Variable_Type_Id = VB.SPC_Group_Variable_Type_Id
+		`
+
+	queryGetVariableIdByPath = `
+		SELECT VB.Var_Id
+		FROM dbo.Variables_Base AS VB
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PLB.PL_Desc = @param_pl_desc
+		AND PUB.PU_Desc = @param_pu_desc
+		AND VB.Var_Desc = @param_var_desc;
+		`
+
+	// Renames and moves between groups happen before the procedure runs, because
+	// spEM_IEImportVariables finds the variable to update by its unit and description. Only
+	// groups of the variable's own unit qualify: specs, display rows and alarms are kept per
+	// unit, so moving to another unit replaces the variable instead (see
+	// resourceVariableCustomizeDiff).
+	queryMoveVariable = `
+		SET XACT_ABORT ON;
+
+		IF NOT EXISTS (
+			SELECT 1
+			FROM dbo.Variables_Base AS VB
+			JOIN dbo.PU_Groups AS PUG ON PUG.PU_Id = VB.PU_Id
+			WHERE VB.Var_Id = @param_var_id
+			AND PUG.PUG_Id = @param_pug_id)
+			THROW 50000, 'unit group belongs to another unit', 1;
+
+		UPDATE dbo.Variables_Base SET
+			Var_Desc = @param_var_desc,
+			PUG_Id = @param_pug_id
+		WHERE Var_Id = @param_var_id;
+		`
+
+	queryUpdateVariableGlobalDesc = `
+		UPDATE dbo.Variables_Base SET
+			Var_Desc_Global = @param_var_desc_global
+		WHERE Var_Id = @param_var_id;
+		`
+
+	queryDeleteVariable = `
+		EXEC	@return_value = [dbo].[spEM_DropVariable]
+				@Var_Id = @param_var_id,
+				@User_Id = @param_user_id;
+		`
+)
+
+var (
+	queryGetVariable          = buildQueryGetVariable("")
+	queryGetVariableForUpdate = buildQueryGetVariable("WITH (UPDLOCK, HOLDLOCK)")
+	queryImportVariable       = buildQueryImportVariable()
+)
+
+func buildQueryGetVariable(hint string) string {
+	columns := []string{"VB.Var_Id", "VB.PUG_Id", "VB.Var_Desc", "VB.Var_Desc_Global", "VB.Spec_Id"}
+	for _, f := range variableFields {
+		if f.Column != "" {
+			columns = append(columns, f.Column)
+		}
+	}
+	from := strings.Replace(queryGetVariableFrom, "dbo.Variables_Base AS VB", "dbo.Variables_Base AS VB "+hint, 1)
+	return "SELECT " + strings.Join(columns, ",\n\t\t\t") + from + "WHERE VB.Var_Id = @param_var_id;"
+}
+
+func buildQueryImportVariable() string {
+	var b strings.Builder
+	b.WriteString(`
+		SET XACT_ABORT ON;
+
+		DECLARE @dept_desc			NVARCHAR(100),
+				@pl_desc			NVARCHAR(50),
+				@pu_desc			NVARCHAR(50),
+				@pug_desc			NVARCHAR(50),
+				@sg_desc			NVARCHAR(100),
+				@ref_pl_desc		NVARCHAR(50),
+				@ref_pu_desc		NVARCHAR(50),
+				@ref_var_desc		NVARCHAR(50),
+				@par_var_desc		NVARCHAR(50),
+				@msg				NVARCHAR(2048);
+
+		SELECT	@dept_desc = DB.Dept_Desc, @pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc, @pug_desc = PUG.PUG_Desc
+		FROM dbo.PU_Groups AS PUG
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = PUG.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		JOIN dbo.Departments_Base AS DB ON DB.Dept_Id = PLB.Dept_Id
+		WHERE PUG.PUG_Id = @param_pug_id;
+
+		IF @pug_desc IS NULL
+			THROW 50000, 'unit group not found', 1;
+
+		SET @sg_desc = (
+			SELECT SG.Group_Desc FROM dbo.Security_Groups AS SG WHERE SG.Group_Id = @param_group_id);
+
+		SELECT	@ref_pl_desc = PLB.PL_Desc, @ref_pu_desc = PUB.PU_Desc, @ref_var_desc = VB.Var_Desc
+		FROM dbo.Variables_Base AS VB
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE VB.Var_Id = @param_ref_var_id;
+
+		SET @par_var_desc = (
+			SELECT VB.Var_Desc FROM dbo.Variables_Base AS VB WHERE VB.Var_Id = @param_par_var_id);
+
+		IF @param_spec_id IS NOT NULL
+		BEGIN
+			SET @p_Spec_Desc = (
+				SELECT PP.Prop_Desc + ' / ' + S.Spec_Desc FROM dbo.Specifications AS S
+				JOIN dbo.Product_Properties AS PP ON PP.Prop_Id = S.Prop_Id
+				WHERE S.Spec_Id = @param_spec_id);
+
+			IF @p_Spec_Desc IS NULL
+				THROW 50000, 'specification not found', 1;
+		END
+`)
+
+	for _, l := range variableLookups {
+		fmt.Fprintf(&b, `
+		IF @p_%[1]s IS NOT NULL AND NOT EXISTS (%[2]s)
+		BEGIN
+			SET @msg = N'%[1]s not found: ' + @p_%[1]s;
+			THROW 50000, @msg, 1;
+		END
+`, l.Param, l.Query)
+	}
+
+	b.WriteString(`
+		EXEC	@return_value = [dbo].[spEM_IEImportVariables]
+				@Dept_Desc = @dept_desc,
+				@PL_Desc = @pl_desc,
+				@PU_Desc = @pu_desc,
+				@PUG_Desc = @pug_desc,
+				@Var_Desc = @param_var_desc,
+				@Group_Desc = @sg_desc,
+				@RefPL_Desc = @ref_pl_desc,
+				@RefPU_Desc = @ref_pu_desc,
+				@RefVar_Desc = @ref_var_desc,
+				@ParVar_Desc = @par_var_desc,
+`)
+	for _, f := range variableFields {
+		fmt.Fprintf(&b, "\t\t\t\t@%[1]s = @p_%[1]s,\n", f.Param)
+	}
+	b.WriteString(`				@UserId = @param_user_id;
+
+		SET @out_Var_Id = (
+			SELECT VB.Var_Id FROM dbo.Variables_Base AS VB
+			JOIN dbo.PU_Groups AS PUG ON PUG.PU_Id = VB.PU_Id
+			WHERE PUG.PUG_Id = @param_pug_id AND VB.Var_Desc = @param_var_desc);
+`)
+	return b.String()
+}
+
+func resourceVariable() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"variable_id": {
+			Type:     schema.TypeInt,
+			Computed: true, // Not settable by user
+		},
+		"unit_group_id": {
+			Type:     schema.TypeInt,
+			Required: true,
+		},
+		"description": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "Var_Desc.",
+			ValidateFunc: validation.StringLenBetween(1, 50),
+		},
+		"description_global": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringLenBetween(1, 50),
+		},
+		"security_group_id": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"reference_variable_id": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Sampling reference variable.",
+		},
+		"parent_variable_id": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"specification_id": {
+			Type:          schema.TypeInt,
+			Optional:      true,
+			Computed:      true,
+			Description:   "Spec_Id, e.g. pa_specification.x.specification_id.",
+			ConflictsWith: []string{"specification"},
+		},
+	}
+	for _, f := range variableFields {
+		s[f.Attribute] = f.Schema
+	}
+	s["sampling_window"].ConflictsWith = []string{"sampling_window_type"}
+	s["sampling_window_type"].ConflictsWith = []string{"sampling_window"}
+	// spEM_IEImportVariables stores DF_TestFreq in Sampling_Interval, so only one may be set.
+	s["sampling_interval"].ConflictsWith = []string{"test_frequency"}
+	s["test_frequency"].ConflictsWith = []string{"sampling_interval"}
+	s["specification"].ConflictsWith = []string{"specification_id"}
+
+	return &schema.Resource{
+		CreateContext: resourceVariableCreate,
+		ReadContext:   resourceVariableRead,
+		UpdateContext: resourceVariableUpdate,
+		DeleteContext: resourceVariableDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVariableImport,
+		},
+		CustomizeDiff: resourceVariableCustomizeDiff,
+		Schema: s,
+	}
+}
+
+func validateSpecPath() schema.SchemaValidateFunc {
+	return func(val interface{}, key string) (warns []string, errs []error) {
+		parts := strings.Split(val.(string), " / ")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			errs = append(errs, fmt.Errorf("%q must have the form \"Property / Spec\"", key))
+		}
+		return
+	}
+}
+
+// resourceVariableCustomizeDiff keeps specification and specification_id in step. Whichever
+// is configured drives the other; with neither configured the specification is cleared.
+// It also checks the sampling type and data type rules the procedure doesn't enforce, and
+// replaces the variable when it moves to another unit.
+func resourceVariableCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return nil
+	}
+
+	if err := checkVariableTypeRules(ctx, d, m); err != nil {
+		return err
+	}
+	if err := forceNewOnUnitChange(ctx, d, m); err != nil {
+		return err
+	}
+
+	switch {
+	case !config.GetAttr("specification_id").IsNull():
+		if d.HasChange("specification_id") {
+			return d.SetNewComputed("specification")
+		}
+	case !config.GetAttr("specification").IsNull():
+		if d.HasChange("specification") {
+			return d.SetNewComputed("specification_id")
+		}
+	default:
+		if d.Get("specification").(string) != "" {
+			if err := d.SetNew("specification", ""); err != nil {
+				return err
+			}
+		}
+		if d.Get("specification_id").(int) != 0 {
+			return d.SetNew("specification_id", 0)
+		}
+	}
+	return nil
+}
+
+// checkVariableTypeRules rejects max_rpm missing for the RPM sampling type (ST_Id 16) and
+// precision set on data types that have none (Data_Type_Id 2 and 7). Both need the lookup
+// tables, so they are skipped until the provider is configured and the values are known.
+func checkVariableTypeRules(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if m == nil {
+		return nil
+	}
+	config := d.GetRawConfig()
+	db := getDB(m)
+
+	stDesc := config.GetAttr("sampling_type")
+	if config.GetAttr("max_rpm").IsNull() && stDesc.IsKnown() && !stDesc.IsNull() {
+		var found int
+		err := db.QueryRowContext(ctx, queryIsRPMSamplingType, sql.Named("param_st_desc", stDesc.AsString())).Scan(&found)
+		if err == nil {
+			return fmt.Errorf("max_rpm must be set when sampling_type is %q", stDesc.AsString())
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+	}
+
+	dataType := config.GetAttr("data_type")
+	if !config.GetAttr("precision").IsNull() && dataType.IsKnown() && !dataType.IsNull() {
+		var found int
+		err := db.QueryRowContext(ctx, queryIsPrecisionlessDataType, sql.Named("param_data_type_desc", dataType.AsString())).Scan(&found)
+		if err == nil {
+			return fmt.Errorf("precision cannot be set when data_type is %q", dataType.AsString())
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+	}
+	return nil
+}
+
+// forceNewOnUnitChange replaces the variable when unit_group_id moves it to a group of another
+// unit. A group that doesn't exist yet can't be looked up, so that counts as another unit too.
+func forceNewOnUnitChange(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("unit_group_id") {
+		return nil
+	}
+	if m == nil || !d.NewValueKnown("unit_group_id") {
+		return d.ForceNew("unit_group_id")
+	}
+
+	o, n := d.GetChange("unit_group_id")
+	db := getDB(m)
+	var oldUnit, newUnit int64
+	if err := db.QueryRowContext(ctx, queryGetUnitGroupUnit, sql.Named("param_pug_id", int64(o.(int)))).Scan(&oldUnit); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if err := db.QueryRowContext(ctx, queryGetUnitGroupUnit, sql.Named("param_pug_id", int64(n.(int)))).Scan(&newUnit); err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	if oldUnit == 0 || oldUnit != newUnit {
+		return d.ForceNew("unit_group_id")
+	}
+	return nil
+}
+
+// isConfigured reports whether attr is set in the configuration. Unlike GetOk it tells an
+// explicit 0 or false apart from an attribute that was left out.
+func isConfigured(d *schema.ResourceData, attr string) bool {
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		_, ok := d.GetOk(attr)
+		return ok
+	}
+	return !config.GetAttr(attr).IsNull()
+}
+
+// procValue renders an attribute the way spEM_IEImportVariables expects it: a string, or
+// NULL when the attribute isn't configured.
+func (f variableField) procValue(d *schema.ResourceData) sql.NullString {
+	switch f.Schema.Type {
+	case schema.TypeBool:
+		return sql.NullString{String: int64ToString(boolToInt64(d.Get(f.Attribute).(bool))), Valid: true}
+	case schema.TypeInt:
+		if !isConfigured(d, f.Attribute) {
+			return sql.NullString{}
+		}
+		return sql.NullString{String: strconv.Itoa(d.Get(f.Attribute).(int)), Valid: true}
+	case schema.TypeFloat:
+		if !isConfigured(d, f.Attribute) {
+			return sql.NullString{}
+		}
+		return sql.NullString{String: strconv.FormatFloat(d.Get(f.Attribute).(float64), 'f', -1, 64), Valid: true}
+	default:
+		return stringToNullString(d.Get(f.Attribute).(string))
+	}
+}
+
+// readVariable returns every readable attribute of a variable keyed by attribute name.
+func readVariable(row interface{ Scan(...interface{}) error }) (map[string]interface{}, error) {
+	var varID int64
+	var pugID sql.NullInt64
+	var description, descriptionGlobal sql.NullString
+	var specID sql.NullInt64
+	dest := []interface{}{&varID, &pugID, &description, &descriptionGlobal, &specID}
+
+	var readable []variableField
+	for _, f := range variableFields {
+		if f.Column == "" {
+			continue
+		}
+		readable = append(readable, f)
+		switch f.Schema.Type {
+		case schema.TypeBool:
+			dest = append(dest, new(sql.NullBool))
+		case schema.TypeInt:
+			dest = append(dest, new(sql.NullInt64))
+		case schema.TypeFloat:
+			dest = append(dest, new(sql.NullFloat64))
+		default:
+			dest = append(dest, new(sql.NullString))
+		}
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{
+		"variable_id":        varID,
+		"unit_group_id":      nullableIdToInt64(pugID),
+		"description":        nullableStringToString(description),
+		"description_global": nullableStringToString(descriptionGlobal),
+		"specification_id":   nullableIdToInt64(specID),
+	}
+	for i, f := range readable {
+		switch v := dest[i+5].(type) {
+		case *sql.NullBool:
+			values[f.Attribute] = v.Valid && v.Bool
+		case *sql.NullInt64:
+			values[f.Attribute] = v.Int64
+		case *sql.NullFloat64:
+			values[f.Attribute] = v.Float64
+		case *sql.NullString:
+			values[f.Attribute] = nullableStringToString(*v)
+		}
+	}
+	return values, nil
+}
+
+func execImportVariable(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) (int64, error) {
+	userId := 1
+
+	// specification_id is Computed, so only pass it when it is actually configured.
+	specID := sql.NullInt64{}
+	if !d.GetRawConfig().GetAttr("specification_id").IsNull() {
+		specID = idToNullInt64(int64(d.Get("specification_id").(int)))
+	}
+
+	var returnValue sql.NullInt64
+	var outVarID sql.NullInt64
+	args := []interface{}{
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_pug_id", int64(d.Get("unit_group_id").(int))),
+		sql.Named("param_var_desc", d.Get("description").(string)),
+		sql.Named("param_group_id", idToNullInt64(int64(d.Get("security_group_id").(int)))),
+		sql.Named("param_ref_var_id", idToNullInt64(int64(d.Get("reference_variable_id").(int)))),
+		sql.Named("param_par_var_id", idToNullInt64(int64(d.Get("parent_variable_id").(int)))),
+		sql.Named("param_spec_id", specID),
+		sql.Named("param_user_id", userId),
+		sql.Named("out_Var_Id", sql.Out{Dest: &outVarID}),
+	}
+	for _, f := range variableFields {
+		args = append(args, sql.Named("p_"+f.Param, f.procValue(d)))
+	}
+
+	if _, err := tx.ExecContext(ctx, queryImportVariable, args...); err != nil {
+		return 0, err
+	}
+	if returnValue.Int64 != 0 || !outVarID.Valid {
+		return 0, fmt.Errorf(
+			"stored procedure returned failure status: return_value=%v, outVarID.Valid=%v",
+			returnValue.Int64, outVarID.Valid)
+	}
+
+	_, err := tx.ExecContext(ctx, queryUpdateVariableGlobalDesc,
+		sql.Named("param_var_id", outVarID.Int64),
+		sql.Named("param_var_desc_global", stringToNullString(d.Get("description_global").(string))),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return outVarID.Int64, nil
+}
+
+func resourceVariableCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var id int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var err error
+		id, err = execImportVariable(ctx, tx, d)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("variable_id", int(id))
+	d.SetId(int64ToString(id))
+	return resourceVariableRead(ctx, d, m)
+}
+
+func resourceVariableRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	values, err := readVariable(db.QueryRowContext(ctx, queryGetVariable, sql.Named("param_var_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for key, value := range values {
+		if err := d.Set(key, value); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	return nil
+}
+
+func resourceVariableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := readVariable(tx.QueryRowContext(ctx, queryGetVariableForUpdate, sql.Named("param_var_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("variable %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		delete(current, "variable_id")
+		if err := checkUnchanged(d, "variable", current); err != nil {
+			return err
+		}
+
+		if d.HasChanges("description", "unit_group_id") {
+			_, err := tx.ExecContext(ctx, queryMoveVariable,
+				sql.Named("param_var_id", id),
+				sql.Named("param_var_desc", d.Get("description").(string)),
+				sql.Named("param_pug_id", int64(d.Get("unit_group_id").(int))),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = execImportVariable(ctx, tx, d)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceVariableRead(ctx, d, m)
+}
+
+func resourceVariableDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	userId := 1
+
+	var returnValue int
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteVariable,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_var_id", id),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return err
+		}
+		if returnValue != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue)
+		}
+		return nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceVariableImport accepts "Line/Unit/Variable".
+func resourceVariableImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Line", "Unit", "Variable")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetVariableIdByPath,
+		sql.Named("param_pl_desc", parts[0]),
+		sql.Named("param_pu_desc", parts[1]),
+		sql.Named("param_var_desc", parts[2]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("variable %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
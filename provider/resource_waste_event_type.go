@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+const (
+	queryGetWasteEventType = `
+		SELECT WET.WET_Name, WET.ReadOnly
+		FROM dbo.Waste_Event_Type AS WET
+		WHERE WET.WET_Id = @param_wet_id;
+		`
+
+	queryGetWasteEventTypeForUpdate = `
+		SELECT WET.WET_Name, WET.ReadOnly
+		FROM dbo.Waste_Event_Type AS WET WITH (UPDLOCK, HOLDLOCK)
+		WHERE WET.WET_Id = @param_wet_id;
+		`
+
+	queryGetWasteEventTypeIdByName = `
+		SELECT WET.WET_Id
+		FROM dbo.Waste_Event_Type AS WET
+		WHERE WET.WET_Name = @param_wet_name;
+		`
+
+	queryCreateWasteEventType = `
+		EXEC	@return_value = [dbo].[spEM_IEImportWasteEventType]
+				@WasteTypeDesc = @param_wet_name,
+				@sReadOnly = @param_read_only,
+				@UserId = @param_user_id;
+		`
+
+	queryUpdateWasteEventType = `
+		UPDATE dbo.Waste_Event_Type SET
+			WET_Name = @param_wet_name,
+			ReadOnly = @param_read_only
+		WHERE WET_Id = @param_wet_id;
+		`
+
+	queryDeleteWasteEventType = `
+		DELETE FROM dbo.Waste_Event_Type
+		WHERE WET_Id = @param_wet_id;
+		`
+)
+
+func resourceWasteEventType() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWasteEventTypeCreate,
+		ReadContext:   resourceWasteEventTypeRead,
+		UpdateContext: resourceWasteEventTypeUpdate,
+		DeleteContext: resourceWasteEventTypeDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWasteEventTypeImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"waste_event_type_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "WET_Name.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"read_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceWasteEventTypeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	userId := 1
+	name := d.Get("name").(string)
+
+	var wetID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetWasteEventTypeIdByName, sql.Named("param_wet_name", name)).Scan(&wetID)
+		if err == nil {
+			return fmt.Errorf("waste event type %q already exists; import it instead", name)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		var returnValue sql.NullInt64
+		_, err = tx.ExecContext(ctx, queryCreateWasteEventType,
+			sql.Named("return_value", sql.Out{Dest: &returnValue}),
+			sql.Named("param_wet_name", name),
+			sql.Named("param_read_only", int64ToString(boolToInt64(d.Get("read_only").(bool)))),
+			sql.Named("param_user_id", userId),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+		if returnValue.Int64 != 0 {
+			return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetWasteEventTypeIdByName, sql.Named("param_wet_name", name)).Scan(&wetID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("waste event type %q was not created", name)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("waste_event_type_id", int(wetID))
+	d.SetId(int64ToString(wetID))
+	return resourceWasteEventTypeRead(ctx, d, m)
+}
+
+func resourceWasteEventTypeRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var name sql.NullString
+	var readOnly sql.NullBool
+	err = db.QueryRowContext(ctx, queryGetWasteEventType, sql.Named("param_wet_id", id)).Scan(&name, &readOnly)
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("waste_event_type_id", id)
+	d.Set("name", nullableStringToString(name))
+	d.Set("read_only", readOnly.Bool)
+	return nil
+}
+
+func resourceWasteEventTypeUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		var name sql.NullString
+		var readOnly sql.NullBool
+		err := tx.QueryRowContext(ctx, queryGetWasteEventTypeForUpdate, sql.Named("param_wet_id", id)).Scan(&name, &readOnly)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("waste event type %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "waste event type", map[string]interface{}{
+			"name":      nullableStringToString(name),
+			"read_only": readOnly.Bool,
+		}); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, queryUpdateWasteEventType,
+			sql.Named("param_wet_id", id),
+			sql.Named("param_wet_name", d.Get("name").(string)),
+			sql.Named("param_read_only", boolToInt64(d.Get("read_only").(bool))),
+		)
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceWasteEventTypeRead(ctx, d, m)
+}
+
+func resourceWasteEventTypeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteWasteEventType, sql.Named("param_wet_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceWasteEventTypeImport accepts the type name.
+func resourceWasteEventTypeImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	var id int64
+	err := getDB(m).QueryRowContext(ctx, queryGetWasteEventTypeIdByName,
+		sql.Named("param_wet_name", d.Id()),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("waste event type %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type WasteFault struct {
+	Fault_Id    int64
+	Unit_Id     int64
+	Value       string
+	Name        string
+	Location_Id int64
+	ReasonIds   []interface{}
+}
+
+const (
+	queryGetWasteFault = `
+		SELECT	WEF.WEFault_Id, WEF.PU_Id, WEF.WEFault_Value, WEF.WEFault_Name, WEF.Source_PU_Id,
+				WEF.Reason_Level1, WEF.Reason_Level2, WEF.Reason_Level3, WEF.Reason_Level4
+		FROM dbo.Waste_Event_Fault AS WEF
+		WHERE WEF.WEFault_Id = @param_fault_id;
+		`
+
+	queryGetWasteFaultForUpdate = `
+		SELECT	WEF.WEFault_Id, WEF.PU_Id, WEF.WEFault_Value, WEF.WEFault_Name, WEF.Source_PU_Id,
+				WEF.Reason_Level1, WEF.Reason_Level2, WEF.Reason_Level3, WEF.Reason_Level4
+		FROM dbo.Waste_Event_Fault AS WEF WITH (UPDLOCK, HOLDLOCK)
+		WHERE WEF.WEFault_Id = @param_fault_id;
+		`
+
+	queryGetWasteFaultIdByValue = `
+		SELECT WEF.WEFault_Id
+		FROM dbo.Waste_Event_Fault AS WEF
+		WHERE WEF.PU_Id = @param_pu_id
+		AND WEF.WEFault_Value = @param_fault_value;
+		`
+
+	queryGetWasteFaultIdByPath = `
+		SELECT WEF.WEFault_Id
+		FROM dbo.Waste_Event_Fault AS WEF
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = WEF.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PLB.PL_Desc = @param_pl_desc
+		AND PUB.PU_Desc = @param_pu_desc
+		AND WEF.WEFault_Value = @param_fault_value;
+		`
+
+	// spEM_IEImportWasteEventFault creates or updates the fault with FaultValue on the unit. The
+	// reasons must already be in the unit's waste reason tree.
+	queryImportWasteFault = `
+		SET XACT_ABORT ON;
+
+		DECLARE @pl_desc			NVARCHAR(100),
+				@pu_desc			NVARCHAR(100),
+				@location_desc		NVARCHAR(100),
+				@r1					NVARCHAR(100),
+				@r2					NVARCHAR(100),
+				@r3					NVARCHAR(100),
+				@r4					NVARCHAR(100);
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc
+		FROM dbo.Prod_Units_Base AS PUB
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+
+		IF @pu_desc IS NULL
+			THROW 50000, 'unit not found', 1;
+
+		IF @param_location_id IS NOT NULL
+		BEGIN
+			SET @location_desc = (
+				SELECT PUB.PU_Desc FROM dbo.Prod_Units_Base AS PUB WHERE PUB.PU_Id = @param_location_id);
+
+			IF @location_desc IS NULL
+				THROW 50000, 'location unit not found', 1;
+		END
+
+		SET @r1 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason1);
+		SET @r2 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason2);
+		SET @r3 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason3);
+		SET @r4 = (SELECT ER.Event_Reason_Name FROM dbo.Event_Reasons AS ER WHERE ER.Event_Reason_Id = @param_reason4);
+
+		IF (@param_reason1 IS NOT NULL AND @r1 IS NULL)
+			OR (@param_reason2 IS NOT NULL AND @r2 IS NULL)
+			OR (@param_reason3 IS NOT NULL AND @r3 IS NULL)
+			OR (@param_reason4 IS NOT NULL AND @r4 IS NULL)
+			THROW 50000, 'event reason not found', 1;
+
+		EXEC	@return_value = [dbo].[spEM_IEImportWasteEventFault]
+				@LineDesc = @pl_desc,
+				@UnitDesc = @pu_desc,
+				@FaultValue = @param_fault_value,
+				@FaultDesc = @param_fault_name,
+				@LocationDesc = @location_desc,
+				@RLevel1 = @r1,
+				@RLevel2 = @r2,
+				@RLevel3 = @r3,
+				@RLevel4 = @r4,
+				@AddReasons = '0',
+				@User_Id = @param_user_id;
+		`
+
+	queryRenameWasteFault = `
+		UPDATE dbo.Waste_Event_Fault SET
+			WEFault_Value = @param_fault_value
+		WHERE WEFault_Id = @param_fault_id;
+		`
+
+	queryDeleteWasteFault = `
+		DELETE FROM dbo.Waste_Event_Fault
+		WHERE WEFault_Id = @param_fault_id;
+		`
+)
+
+func resourceWasteFault() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWasteFaultCreate,
+		ReadContext:   resourceWasteFaultRead,
+		UpdateContext: resourceWasteFaultUpdate,
+		DeleteContext: resourceWasteFaultDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWasteFaultImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"fault_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"unit_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"value": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "WEFault_Value, the value the PLC reports.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "WEFault_Name.",
+				ValidateFunc: validation.StringLenBetween(0, 100),
+			},
+			"location_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Unit the waste is assigned to.",
+			},
+			"reason_ids": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    reasonTreeDepth,
+				Description: "Reason path, from level 1 down.",
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+// scanFaultReasons collects the leading non-null reason levels as reason_ids.
+func scanFaultReasons(levels []sql.NullInt64) []interface{} {
+	reasonIDs := []interface{}{}
+	for _, reasonID := range levels {
+		if !reasonID.Valid {
+			break
+		}
+		reasonIDs = append(reasonIDs, int(reasonID.Int64))
+	}
+	return reasonIDs
+}
+
+// faultReasonArgs renders reason_ids as the @param_reason1..4 values; missing levels are NULL.
+func faultReasonArgs(reasonIDs []interface{}) []interface{} {
+	var args []interface{}
+	for i := 0; i < reasonTreeDepth; i++ {
+		value := sql.NullInt64{}
+		if i < len(reasonIDs) {
+			value = sql.NullInt64{Int64: int64(reasonIDs[i].(int)), Valid: true}
+		}
+		args = append(args, sql.Named(fmt.Sprintf("param_reason%d", i+1), value))
+	}
+	return args
+}
+
+func scanWasteFault(row interface{ Scan(...interface{}) error }) (*WasteFault, error) {
+	var fault WasteFault
+	var value, name sql.NullString
+	var locationID sql.NullInt64
+	levels := make([]sql.NullInt64, reasonTreeDepth)
+	if err := row.Scan(&fault.Fault_Id, &fault.Unit_Id, &value, &name, &locationID,
+		&levels[0], &levels[1], &levels[2], &levels[3]); err != nil {
+		return nil, err
+	}
+	fault.Value = nullableStringToString(value)
+	fault.Name = nullableStringToString(name)
+	fault.Location_Id = nullableIdToInt64(locationID)
+	fault.ReasonIds = scanFaultReasons(levels)
+	return &fault, nil
+}
+
+func execImportWasteFault(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) error {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	args := append(faultReasonArgs(d.Get("reason_ids").([]interface{})),
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_pu_id", int64(d.Get("unit_id").(int))),
+		sql.Named("param_fault_value", d.Get("value").(string)),
+		sql.Named("param_fault_name", stringToNullString(d.Get("name").(string))),
+		sql.Named("param_location_id", idToNullInt64(int64(d.Get("location_id").(int)))),
+		sql.Named("param_user_id", userId),
+	)
+	if _, err := tx.ExecContext(ctx, queryImportWasteFault, args...); err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceWasteFaultCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unitID := int64(d.Get("unit_id").(int))
+	value := d.Get("value").(string)
+
+	var faultID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetWasteFaultIdByValue,
+			sql.Named("param_pu_id", unitID),
+			sql.Named("param_fault_value", value),
+		).Scan(&faultID)
+		if err == nil {
+			return fmt.Errorf("unit %d already has a waste fault %q; import it instead", unitID, value)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportWasteFault(ctx, tx, d); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetWasteFaultIdByValue,
+			sql.Named("param_pu_id", unitID),
+			sql.Named("param_fault_value", value),
+		).Scan(&faultID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("waste fault %q was not created", value)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("fault_id", int(faultID))
+	d.SetId(int64ToString(faultID))
+	return resourceWasteFaultRead(ctx, d, m)
+}
+
+func resourceWasteFaultRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	fault, err := scanWasteFault(db.QueryRowContext(ctx, queryGetWasteFault, sql.Named("param_fault_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("fault_id", fault.Fault_Id)
+	d.Set("unit_id", fault.Unit_Id)
+	d.Set("value", fault.Value)
+	d.Set("name", fault.Name)
+	d.Set("location_id", fault.Location_Id)
+	d.Set("reason_ids", fault.ReasonIds)
+	return nil
+}
+
+func resourceWasteFaultUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanWasteFault(tx.QueryRowContext(ctx, queryGetWasteFaultForUpdate, sql.Named("param_fault_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("waste fault %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "waste fault", map[string]interface{}{
+			"value":       current.Value,
+			"name":        current.Name,
+			"location_id": current.Location_Id,
+			"reason_ids":  current.ReasonIds,
+		}); err != nil {
+			return err
+		}
+
+		// The procedure finds the fault by value, so a new value is written first.
+		if d.HasChange("value") {
+			_, err := tx.ExecContext(ctx, queryRenameWasteFault,
+				sql.Named("param_fault_id", id),
+				sql.Named("param_fault_value", d.Get("value").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return execImportWasteFault(ctx, tx, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceWasteFaultRead(ctx, d, m)
+}
+
+func resourceWasteFaultDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteWasteFault, sql.Named("param_fault_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceWasteFaultImport accepts "Line/Unit/FaultValue".
+func resourceWasteFaultImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Line", "Unit", "FaultValue")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetWasteFaultIdByPath,
+		sql.Named("param_pl_desc", parts[0]),
+		sql.Named("param_pu_desc", parts[1]),
+		sql.Named("param_fault_value", parts[2]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("waste fault %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
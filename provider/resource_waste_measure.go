@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+type WasteMeasure struct {
+	Measure_Id  int64
+	Unit_Id     int64
+	Name        string
+	Conversion  float64
+	Variable_Id int64
+}
+
+const (
+	queryGetWasteMeasure = `
+		SELECT WEM.WEMT_Id, WEM.PU_Id, WEM.WEMT_Name_Local, WEM.Conversion, WEM.Conversion_Spec
+		FROM dbo.Waste_Event_Meas AS WEM
+		WHERE WEM.WEMT_Id = @param_wemt_id;
+		`
+
+	queryGetWasteMeasureForUpdate = `
+		SELECT WEM.WEMT_Id, WEM.PU_Id, WEM.WEMT_Name_Local, WEM.Conversion, WEM.Conversion_Spec
+		FROM dbo.Waste_Event_Meas AS WEM WITH (UPDLOCK, HOLDLOCK)
+		WHERE WEM.WEMT_Id = @param_wemt_id;
+		`
+
+	queryGetWasteMeasureIdByName = `
+		SELECT WEM.WEMT_Id
+		FROM dbo.Waste_Event_Meas AS WEM
+		WHERE WEM.PU_Id = @param_pu_id
+		AND WEM.WEMT_Name_Local = @param_wemt_name;
+		`
+
+	queryGetWasteMeasureIdByPath = `
+		SELECT WEM.WEMT_Id
+		FROM dbo.Waste_Event_Meas AS WEM
+		JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = WEM.PU_Id
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PLB.PL_Desc = @param_pl_desc
+		AND PUB.PU_Desc = @param_pu_desc
+		AND WEM.WEMT_Name_Local = @param_wemt_name;
+		`
+
+	// spEM_IEImportWasteEventMeasure creates or updates the measure named WasteMesDesc on the
+	// unit. The conversion variable is looked up by its own line and unit.
+	queryImportWasteMeasure = `
+		SET XACT_ABORT ON;
+
+		DECLARE @pl_desc			NVARCHAR(100),
+				@pu_desc			NVARCHAR(100),
+				@conv_pl_desc		NVARCHAR(100),
+				@conv_pu_desc		NVARCHAR(100),
+				@conv_var_desc		NVARCHAR(100);
+
+		SELECT	@pl_desc = PLB.PL_Desc, @pu_desc = PUB.PU_Desc
+		FROM dbo.Prod_Units_Base AS PUB
+		JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+		WHERE PUB.PU_Id = @param_pu_id;
+
+		IF @pu_desc IS NULL
+			THROW 50000, 'unit not found', 1;
+
+		IF @param_var_id IS NOT NULL
+		BEGIN
+			SELECT	@conv_pl_desc = PLB.PL_Desc, @conv_pu_desc = PUB.PU_Desc, @conv_var_desc = VB.Var_Desc
+			FROM dbo.Variables_Base AS VB
+			JOIN dbo.Prod_Units_Base AS PUB ON PUB.PU_Id = VB.PU_Id
+			JOIN dbo.Prod_Lines_Base AS PLB ON PLB.PL_Id = PUB.PL_Id
+			WHERE VB.Var_Id = @param_var_id;
+
+			IF @conv_var_desc IS NULL
+				THROW 50000, 'conversion variable not found', 1;
+		END
+
+		EXEC	@return_value = [dbo].[spEM_IEImportWasteEventMeasure]
+				@LineDesc = @pl_desc,
+				@UnitDesc = @pu_desc,
+				@WasteMesDesc = @param_wemt_name,
+				@sConversion = @param_conversion,
+				@ConversionLineDesc = @conv_pl_desc,
+				@ConversionUnitDesc = @conv_pu_desc,
+				@ConversionVarDesc = @conv_var_desc,
+				@UserId = @param_user_id;
+		`
+
+	queryRenameWasteMeasure = `
+		UPDATE dbo.Waste_Event_Meas SET
+			WEMT_Name_Local = @param_wemt_name
+		WHERE WEMT_Id = @param_wemt_id;
+		`
+
+	queryDeleteWasteMeasure = `
+		DELETE FROM dbo.Waste_Event_Meas
+		WHERE WEMT_Id = @param_wemt_id;
+		`
+)
+
+func resourceWasteMeasure() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceWasteMeasureCreate,
+		ReadContext:   resourceWasteMeasureRead,
+		UpdateContext: resourceWasteMeasureUpdate,
+		DeleteContext: resourceWasteMeasureDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceWasteMeasureImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"measure_id": {
+				Type:     schema.TypeInt,
+				Computed: true, // Not settable by user
+			},
+			"unit_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "WEMT_Name_Local.",
+				ValidateFunc: validation.StringLenBetween(1, 100),
+			},
+			"conversion": {
+				Type:        schema.TypeFloat,
+				Optional:    true,
+				Default:     1.0,
+				Description: "Factor from the measure to the unit's production units.",
+			},
+			"conversion_variable_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Variable whose value is used as the conversion instead of the fixed factor.",
+			},
+		},
+	}
+}
+
+func scanWasteMeasure(row interface{ Scan(...interface{}) error }) (*WasteMeasure, error) {
+	var measure WasteMeasure
+	var name sql.NullString
+	var conversion sql.NullFloat64
+	var varID sql.NullInt64
+	if err := row.Scan(&measure.Measure_Id, &measure.Unit_Id, &name, &conversion, &varID); err != nil {
+		return nil, err
+	}
+	measure.Name = nullableStringToString(name)
+	// Conversion is a real; round it the same way so 0.1 reads back as 0.1.
+	measure.Conversion, _ = strconv.ParseFloat(strconv.FormatFloat(conversion.Float64, 'g', -1, 32), 64)
+	measure.Variable_Id = nullableIdToInt64(varID)
+	return &measure, nil
+}
+
+func execImportWasteMeasure(ctx context.Context, tx *sql.Tx, d *schema.ResourceData) error {
+	userId := 1
+
+	var returnValue sql.NullInt64
+	_, err := tx.ExecContext(ctx, queryImportWasteMeasure,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_pu_id", int64(d.Get("unit_id").(int))),
+		sql.Named("param_wemt_name", d.Get("name").(string)),
+		sql.Named("param_conversion", strconv.FormatFloat(d.Get("conversion").(float64), 'g', -1, 64)),
+		sql.Named("param_var_id", idToNullInt64(int64(d.Get("conversion_variable_id").(int)))),
+		sql.Named("param_user_id", userId),
+	)
+	if err != nil {
+		return err
+	}
+	if returnValue.Int64 != 0 {
+		return fmt.Errorf("stored procedure returned failure status: %d", returnValue.Int64)
+	}
+	return nil
+}
+
+func resourceWasteMeasureCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	unitID := int64(d.Get("unit_id").(int))
+	name := d.Get("name").(string)
+
+	var measureID int64
+	err := withTransaction(ctx, m, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, queryGetWasteMeasureIdByName,
+			sql.Named("param_pu_id", unitID),
+			sql.Named("param_wemt_name", name),
+		).Scan(&measureID)
+		if err == nil {
+			return fmt.Errorf("unit %d already has a waste measure %q; import it instead", unitID, name)
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		if err := execImportWasteMeasure(ctx, tx, d); err != nil {
+			return fmt.Errorf("failed to execute create logic: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, queryGetWasteMeasureIdByName,
+			sql.Named("param_pu_id", unitID),
+			sql.Named("param_wemt_name", name),
+		).Scan(&measureID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("waste measure %q was not created", name)
+		}
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("measure_id", int(measureID))
+	d.SetId(int64ToString(measureID))
+	return resourceWasteMeasureRead(ctx, d, m)
+}
+
+func resourceWasteMeasureRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	db := getDB(m)
+
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	measure, err := scanWasteMeasure(db.QueryRowContext(ctx, queryGetWasteMeasure, sql.Named("param_wemt_id", id)))
+	if err == sql.ErrNoRows {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("measure_id", measure.Measure_Id)
+	d.Set("unit_id", measure.Unit_Id)
+	d.Set("name", measure.Name)
+	d.Set("conversion", measure.Conversion)
+	d.Set("conversion_variable_id", measure.Variable_Id)
+	return nil
+}
+
+func resourceWasteMeasureUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		current, err := scanWasteMeasure(tx.QueryRowContext(ctx, queryGetWasteMeasureForUpdate, sql.Named("param_wemt_id", id)))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("waste measure %d no longer exists", id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := checkUnchanged(d, "waste measure", map[string]interface{}{
+			"name":                   current.Name,
+			"conversion":             current.Conversion,
+			"conversion_variable_id": current.Variable_Id,
+		}); err != nil {
+			return err
+		}
+
+		// The procedure finds the measure by name, so a rename happens first.
+		if d.HasChange("name") {
+			_, err := tx.ExecContext(ctx, queryRenameWasteMeasure,
+				sql.Named("param_wemt_id", id),
+				sql.Named("param_wemt_name", d.Get("name").(string)),
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return execImportWasteMeasure(ctx, tx, d)
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceWasteMeasureRead(ctx, d, m)
+}
+
+func resourceWasteMeasureDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	id, err := stringToInt64(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = withTransaction(ctx, m, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, queryDeleteWasteMeasure, sql.Named("param_wemt_id", id))
+		return err
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceWasteMeasureImport accepts "Line/Unit/Measure".
+func resourceWasteMeasureImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts, err := splitImportPath(d.Id(), "Line", "Unit", "Measure")
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = getDB(m).QueryRowContext(ctx, queryGetWasteMeasureIdByPath,
+		sql.Named("param_pl_desc", parts[0]),
+		sql.Named("param_pu_desc", parts[1]),
+		sql.Named("param_wemt_name", parts[2]),
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("waste measure %q not found", d.Id())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.SetId(int64ToString(id))
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// specLimitFields are the limit attributes shared by variable and central specifications, in
+// the order of the columns that hold them.
+var specLimitFields = []string{
+	"l_entry", "l_reject", "l_warning", "l_user", "target",
+	"u_user", "u_warning", "u_reject", "u_entry",
+	"l_control", "t_control", "u_control",
+}
+
+// specLimitOrder is checked at plan time; limits that are unset or not numeric are skipped.
+var specLimitOrder = []string{"l_reject", "l_warning", "target", "u_warning", "u_reject"}
+
+// specLimitsSchema returns the limit attributes plus test frequency and e-signature level.
+// Limits are strings because string variables take string specifications.
+func specLimitsSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"test_frequency": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"esignature_level": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "0 for none, 1 for user, 2 for approver.",
+			ValidateFunc: validation.IntBetween(0, 2),
+		},
+	}
+	for _, field := range specLimitFields {
+		s[field] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringLenBetween(0, 25),
+		}
+	}
+	return s
+}
+
+// validateSpecLimitOrder checks L_Reject <= L_Warning <= Target <= U_Warning <= U_Reject.
+func validateSpecLimitOrder(what string, limits map[string]interface{}) error {
+	var prevField string
+	var prevValue float64
+	for _, field := range specLimitOrder {
+		raw, _ := limits[field].(string)
+		value, err := strconv.ParseFloat(raw, 64)
+		if raw == "" || err != nil {
+			continue
+		}
+		if prevField != "" && value < prevValue {
+			return fmt.Errorf("%s: %s (%s) must not be below %s (%s)",
+				what, field, raw, prevField, strconv.FormatFloat(prevValue, 'f', -1, 64))
+		}
+		prevField, prevValue = field, value
+	}
+	return nil
+}
+
+// scanSpecLimits reads the leading columns into lead, then the limit columns in
+// specLimitFields order followed by Test_Freq and Esignature_Level.
+func scanSpecLimits(row interface{ Scan(...interface{}) error }, lead ...interface{}) (map[string]interface{}, error) {
+	var testFreq, esigLevel sql.NullInt64
+	values := make([]sql.NullString, len(specLimitFields))
+	dest := lead
+	for i := range values {
+		dest = append(dest, &values[i])
+	}
+	dest = append(dest, &testFreq, &esigLevel)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	limits := map[string]interface{}{
+		"test_frequency":   int(testFreq.Int64),
+		"esignature_level": int(esigLevel.Int64),
+	}
+	for i, field := range specLimitFields {
+		limits[field] = nullableStringToString(values[i])
+	}
+	return limits, nil
+}
+
+// specLimitArgs renders limits as the @p_<field> strings the import procedures take; unset
+// values are NULL.
+func specLimitArgs(limits map[string]interface{}) []interface{} {
+	var args []interface{}
+	for _, field := range specLimitFields {
+		value, _ := limits[field].(string)
+		args = append(args, sql.Named("p_"+field, stringToNullString(value)))
+	}
+	for _, field := range []string{"test_frequency", "esignature_level"} {
+		value := sql.NullString{}
+		if v, _ := limits[field].(int); v != 0 {
+			value = sql.NullString{String: strconv.Itoa(v), Valid: true}
+		}
+		args = append(args, sql.Named("p_"+field, value))
+	}
+	return args
+}
+
+// hasSpecLimits reports whether any limit, test frequency or e-signature level is set. A pair
+// with none of them has no active spec row, so it reads back as absent.
+func hasSpecLimits(limits map[string]interface{}) bool {
+	for _, field := range specLimitFields {
+		if value, _ := limits[field].(string); value != "" {
+			return true
+		}
+	}
+	for _, field := range []string{"test_frequency", "esignature_level"} {
+		if value, _ := limits[field].(int); value != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func sameSpecLimits(a, b map[string]interface{}) bool {
+	for _, field := range append([]string{"test_frequency", "esignature_level"}, specLimitFields...) {
+		if fmt.Sprint(a[field]) != fmt.Sprint(b[field]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSpecLimitOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "ordered",
+			limits: map[string]interface{}{"l_reject": "1", "l_warning": "2", "target": "3", "u_warning": "4", "u_reject": "5"},
+		},
+		{
+			name:   "equal limits",
+			limits: map[string]interface{}{"l_reject": "3", "target": "3", "u_reject": "3"},
+		},
+		{
+			name:   "unset limits are skipped",
+			limits: map[string]interface{}{"l_reject": "1", "l_warning": "", "u_reject": "9"},
+		},
+		{
+			name:   "non-numeric limits are skipped",
+			limits: map[string]interface{}{"l_reject": "1", "target": "Blue", "u_reject": "9"},
+		},
+		{
+			name:   "limits outside the order are ignored",
+			limits: map[string]interface{}{"l_entry": "100", "target": "3", "u_entry": "0"},
+		},
+		{
+			name:    "target below lower warning",
+			limits:  map[string]interface{}{"l_warning": "5", "target": "4.5"},
+			wantErr: "weight: target (4.5) must not be below l_warning (5)",
+		},
+		{
+			name:    "compared across an unset limit",
+			limits:  map[string]interface{}{"l_reject": "10", "target": "", "u_reject": "2"},
+			wantErr: "u_reject (2) must not be below l_reject (10)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSpecLimitOrder("weight", tt.limits)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSameSpecLimits(t *testing.T) {
+	active := func() map[string]interface{} {
+		limits := map[string]interface{}{"test_frequency": 1, "esignature_level": 0}
+		for _, field := range specLimitFields {
+			limits[field] = ""
+		}
+		limits["target"] = "10"
+		return limits
+	}
+
+	if !sameSpecLimits(active(), active()) {
+		t.Error("identical limits compare as different")
+	}
+
+	changes := map[string]func(map[string]interface{}){
+		"target changed":         func(l map[string]interface{}) { l["target"] = "11" },
+		"u_reject set":           func(l map[string]interface{}) { l["u_reject"] = "12" },
+		"test_frequency changed": func(l map[string]interface{}) { l["test_frequency"] = 2 },
+		"esignature_level set":   func(l map[string]interface{}) { l["esignature_level"] = 1 },
+		// Limits are compared as text, the way PA stores them.
+		"target rewritten as 10.0": func(l map[string]interface{}) { l["target"] = "10.0" },
+	}
+	for name, change := range changes {
+		limits := active()
+		change(limits)
+		if sameSpecLimits(active(), limits) {
+			t.Errorf("%s: limits compare as the same", name)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	queryGetAppLock = `
+		EXEC @return_value = sp_getapplock
+			@Resource = @param_resource,
+			@LockMode = 'Exclusive',
+			@LockOwner = 'Transaction',
+			@LockTimeout = @param_timeout_ms;
+		`
+
+	queryCheckAppLock = `
+		SELECT APPLOCK_MODE('public', @param_resource, 'Transaction');
+		`
+
+	// Application locks show up in sys.dm_tran_locks with the first 32 characters of the
+	// resource name in square brackets, which is enough to find the holding session.
+	queryGetAppLockHolder = `
+		SELECT TOP (1) S.session_id, S.login_name, S.host_name, S.program_name
+		FROM sys.dm_tran_locks AS L
+		JOIN sys.dm_exec_sessions AS S ON S.session_id = L.request_session_id
+		WHERE L.resource_type = 'APPLICATION'
+		AND L.request_status = 'GRANT'
+		AND L.resource_database_id = DB_ID()
+		AND CHARINDEX(N'[' + LEFT(@param_resource, 32) + N']', L.resource_description) > 0;
+		`
+)
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx, for lookups that run at plan time
+// as well as inside a write.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// withTransaction runs fn inside an explicit transaction. The transaction is rolled back
+// if fn returns an error or the commit fails, so a multi-statement write either lands
+// completely or not at all. When the provider has app_lock enabled, the application lock is
+// taken as the transaction's first statement and released with the commit or rollback.
+func withTransaction(ctx context.Context, m interface{}, fn func(tx *sql.Tx) error) error {
+	meta := getMeta(m)
+	tx, err := meta.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if meta.AppLock {
+		if err := getAppLock(ctx, meta, tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	// The lock belongs to the transaction, so it can only be gone if the session was reset
+	// underneath us; don't commit writes that were not serialized.
+	if meta.AppLock {
+		if err := checkAppLock(ctx, meta, tx); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func getAppLock(ctx context.Context, meta *ProviderMeta, tx *sql.Tx) error {
+	var returnValue int64
+	_, err := tx.ExecContext(ctx, queryGetAppLock,
+		sql.Named("return_value", sql.Out{Dest: &returnValue}),
+		sql.Named("param_resource", meta.AppLockName),
+		sql.Named("param_timeout_ms", meta.AppLockTimeout*1000),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to request application lock %q: %w", meta.AppLockName, err)
+	}
+
+	switch returnValue {
+	case 0, 1:
+		return nil
+	case -1:
+		return fmt.Errorf("timed out after %ds waiting for application lock %q, %s",
+			meta.AppLockTimeout, meta.AppLockName, getAppLockHolder(ctx, meta))
+	case -3:
+		return fmt.Errorf("chosen as deadlock victim while waiting for application lock %q", meta.AppLockName)
+	default:
+		return fmt.Errorf("sp_getapplock returned %d for application lock %q", returnValue, meta.AppLockName)
+	}
+}
+
+// checkAppLock fails unless the transaction still holds the application lock exclusively.
+func checkAppLock(ctx context.Context, meta *ProviderMeta, tx *sql.Tx) error {
+	var mode string
+	if err := tx.QueryRowContext(ctx, queryCheckAppLock, sql.Named("param_resource", meta.AppLockName)).Scan(&mode); err != nil {
+		return fmt.Errorf("failed to check application lock %q: %w", meta.AppLockName, err)
+	}
+	if mode != "Exclusive" {
+		return fmt.Errorf("application lock %q was lost before commit (mode %s); nothing was written", meta.AppLockName, mode)
+	}
+	return nil
+}
+
+// getAppLockHolder describes the session holding the application lock. It is best effort:
+// reading the DMVs needs VIEW SERVER STATE, and the holder may release the lock before we look.
+func getAppLockHolder(ctx context.Context, meta *ProviderMeta) string {
+	var sessionID int64
+	var loginName, hostName, programName sql.NullString
+
+	err := meta.DB.QueryRowContext(ctx, queryGetAppLockHolder,
+		sql.Named("param_resource", meta.AppLockName),
+	).Scan(&sessionID, &loginName, &hostName, &programName)
+	if err == sql.ErrNoRows {
+		return "but it has since been released"
+	}
+	if err != nil {
+		return fmt.Sprintf("and the holder could not be determined: %v", err)
+	}
+
+	return fmt.Sprintf("held by session %d (login %q, host %q, program %q)",
+		sessionID,
+		nullableStringToString(loginName),
+		nullableStringToString(hostName),
+		nullableStringToString(programName))
+}
@@ -36,6 +36,16 @@ func validateVarchar255() schema.SchemaValidateFunc {
 	}
 }
 
+func validateVarchar50() schema.SchemaValidateFunc {
+	return func(val interface{}, key string) (warns []string, errs []error) {
+		v := val.(string)
+		if len(v) > 50 {
+			errs = append(errs, fmt.Errorf("%q must be less than or equal to 50 characters, or empty", key))
+		}
+		return
+	}
+}
+
 func validateTimeZone() schema.SchemaValidateFunc {
 	return validation.StringInSlice([]string{
 		"Eastern Standard Time", "Pacific Standard Time", "UTC",
@@ -0,0 +1,61 @@
+resource "pa_event_reason" "mechanical" {
+  name = "Mechanical"
+  code = "MECH"
+}
+
+resource "pa_event_reason" "electrical" {
+  name = "Electrical"
+  code = "ELEC"
+}
+
+resource "pa_event_reason" "motor" {
+  name             = "Motor Failure"
+  comment_required = true
+}
+
+resource "pa_event_reason" "jam" {
+  name = "Jam"
+}
+
+
+resource "pa_reason_tree" "downtime" {
+  name              = "Downtime Reasons"
+  security_group_id = pa_security_group.operators.group_id
+  level_names       = ["Category", "Cause"]
+
+  node {
+    reason_id = pa_event_reason.mechanical.reason_id
+
+    node {
+      reason_id = pa_event_reason.jam.reason_id
+    }
+  }
+
+  node {
+    reason_id = pa_event_reason.electrical.reason_id
+
+    node {
+      reason_id = pa_event_reason.motor.reason_id
+    }
+  }
+}
+
+
+resource "pa_reason_category" "unplanned" {
+  description = "Unplanned Downtime"
+}
+
+resource "pa_reason_tree_category" "mechanical_unplanned" {
+  tree_id     = pa_reason_tree.downtime.tree_id
+  reason_ids  = [pa_event_reason.mechanical.reason_id]
+  category_id = pa_reason_category.unplanned.category_id
+}
+
+
+resource "pa_reason_shortcut" "jam" {
+  unit_id    = pa_unit.unit1.unit_id
+  name       = "Jam"
+  event_type = "downtime"
+  amount     = 5
+  reason_ids = [pa_event_reason.mechanical.reason_id, pa_event_reason.jam.reason_id]
+}
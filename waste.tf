@@ -0,0 +1,18 @@
+resource "pa_waste_event_type" "scrap" {
+  name = "Scrap"
+}
+
+resource "pa_waste_measure" "boxes" {
+  unit_id                = pa_unit.unit1.unit_id
+  name                   = "Boxes"
+  conversion             = 12
+  conversion_variable_id = pa_variable.weight.variable_id
+}
+
+resource "pa_waste_fault" "reject" {
+  unit_id     = pa_unit.unit1.unit_id
+  value       = "101"
+  name        = "Reject Station"
+  location_id = pa_unit.unit1.unit_id
+  reason_ids  = [pa_event_reason.mechanical.reason_id, pa_event_reason.jam.reason_id]
+}